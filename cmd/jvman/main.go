@@ -1,20 +1,31 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 
 	"github.com/maskedsyntax/jvman/internal/cache"
 	"github.com/maskedsyntax/jvman/internal/config"
+	"github.com/maskedsyntax/jvman/internal/discover"
 	"github.com/maskedsyntax/jvman/internal/downloader"
 	"github.com/maskedsyntax/jvman/internal/extractor"
+	"github.com/maskedsyntax/jvman/internal/fsutil"
+	"github.com/maskedsyntax/jvman/internal/jdkversion"
+	"github.com/maskedsyntax/jvman/internal/lifecycle"
 	"github.com/maskedsyntax/jvman/internal/paths"
+	"github.com/maskedsyntax/jvman/internal/platform"
 	"github.com/maskedsyntax/jvman/internal/provider"
 	"github.com/maskedsyntax/jvman/internal/provider/corretto"
 	"github.com/maskedsyntax/jvman/internal/provider/temurin"
@@ -35,11 +46,8 @@ var vendors = map[string]func() provider.Vendor{
 	"zulu":     func() provider.Vendor { return zulu.New() },
 }
 
-var versionNameFuncs = map[string]func(string) string{
-	"temurin":  temurin.VersionName,
-	"corretto": corretto.VersionName,
-	"zulu":     zulu.VersionName,
-}
+// defaultVendors is the vendor preference used until one is configured.
+var defaultVendors = []string{"temurin", "corretto", "zulu"}
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
@@ -56,25 +64,77 @@ var rootCmd = &cobra.Command{
 var (
 	installVendor string
 	installArch   string
+	installImage  string
+	installJavaFX bool
+	installLibC   string
+	installChan   string
+	installRedo   bool
+	installForce  bool
+	installSystem bool
 	listVendor    string
 	listRefresh   bool
+	listAll       bool
+	whichHome     bool
+	removeForce   bool
+	removeSystem  bool
+	updateAll     bool
+	updateKeep    bool
+	pruneKeep     int
+	pruneUnref    bool
+	pruneDryRun   bool
+	importScan    bool
+	importDryRun  bool
+	globalForce   bool
+	useForce      bool
 )
 
 func init() {
-	installCmd.Flags().StringVarP(&installVendor, "vendor", "v", "temurin", "JDK vendor (temurin, corretto, zulu)")
+	installCmd.Flags().StringVarP(&installVendor, "vendor", "v", "", "JDK vendor (temurin, corretto, zulu); defaults to trying each in the order set by 'jvman config set vendors'")
 	installCmd.Flags().StringVarP(&installArch, "arch", "a", "", "Architecture (x64, aarch64)")
+	installCmd.Flags().StringVarP(&installImage, "image-type", "i", "", "Image type (jdk, jre, testimage, debugimage)")
+	installCmd.Flags().BoolVar(&installJavaFX, "javafx", false, "Install a build with JavaFX bundled (zulu)")
+	installCmd.Flags().StringVar(&installLibC, "libc", "", "C library on Linux (glibc, musl); defaults to the host's")
+	installCmd.Flags().StringVar(&installChan, "channel", "", "Release channel (ga, ea)")
+	installCmd.Flags().BoolVar(&installRedo, "reinstall", false, "Download and unpack again if already installed")
+	installCmd.Flags().BoolVarP(&installForce, "force", "f", false, "Allow reinstalling a pinned installation")
+	installCmd.Flags().BoolVar(&installSystem, "system", false, "Install into the system store shared by all users")
+	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Remove even if pinned")
+	removeCmd.Flags().BoolVar(&removeSystem, "system", false, "Remove from the system store shared by all users")
 	listCmd.Flags().StringVarP(&listVendor, "vendor", "v", "", "Filter by vendor (temurin, corretto, zulu)")
 	listCmd.Flags().BoolVarP(&listRefresh, "refresh", "r", false, "Bypass cache and fetch fresh data")
+	listCmd.Flags().BoolVar(&listAll, "all", false, "List every patch release of the given version")
+	globalCmd.Flags().BoolVarP(&globalForce, "force", "f", false, "Allow a build this machine cannot run")
+	useCmd.Flags().BoolVarP(&useForce, "force", "f", false, "Allow a build this machine cannot run")
+	whichCmd.Flags().BoolVar(&whichHome, "home", false, "Print only the JAVA_HOME of the active installation")
+	updateCmd.Flags().BoolVar(&updateAll, "all", false, "Update every outdated installation")
+	updateCmd.Flags().BoolVar(&updateKeep, "keep", false, "Keep the old installation after updating")
+	pruneCmd.Flags().IntVar(&pruneKeep, "keep", 0, "Keep the newest N patches of each vendor, major and variant")
+	pruneCmd.Flags().BoolVar(&pruneUnref, "unreferenced", false, "Remove every installation that is not in use")
+	pruneCmd.MarkFlagsMutuallyExclusive("keep", "unreferenced")
+	pruneCmd.Flags().BoolVarP(&pruneDryRun, "dry-run", "n", false, "Show what would be removed without removing it")
+	importCmd.Flags().BoolVar(&importScan, "scan", false, "Search the usual install locations and the given directories")
+	importCmd.Flags().BoolVarP(&importDryRun, "dry-run", "n", false, "Show what would be imported without importing it")
 
 	rootCmd.AddCommand(installCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(outdatedCmd)
+	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(globalCmd)
 	rootCmd.AddCommand(useCmd)
 	rootCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(relocateCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(linkCmd)
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+	rootCmd.AddCommand(aliasCmd)
+	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(whichCmd)
 	rootCmd.AddCommand(execCmd)
 	rootCmd.AddCommand(tuiCmd)
 	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(lifecycleCmd)
 	rootCmd.AddCommand(upgradeCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(initCmd)
@@ -83,7 +143,7 @@ func init() {
 var installCmd = &cobra.Command{
 	Use:   "install <version>",
 	Short: "Install a Java version",
-	Long:  "Download and install a specific Java version.\n\nExamples:\n  jvman install 21\n  jvman install 17 --vendor=corretto\n  jvman install 11 -v zulu\n  jvman install 21 --arch=aarch64",
+	Long:  "Download and install a specific Java version. With --system, it is installed into the system store (/opt/jvman, or $JVMAN_SYSTEM_HOME) that every user can select from.\n\nExamples:\n  jvman install 21\n  jvman install 17 --vendor=corretto\n  jvman install 11 -v zulu\n  jvman install corretto@lts\n  jvman install \">=17 <22\"\n  jvman install 21 --arch=aarch64\n  jvman install 21-jre\n  jvman install 21 --vendor=zulu --javafx\n  jvman install 25-ea\n  jvman install 17.0.8+7\n  jvman install corretto@17.0.8.8.1\n  sudo jvman install 21 --system",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runInstall,
 }
@@ -91,12 +151,22 @@ var installCmd = &cobra.Command{
 func runInstall(cmd *cobra.Command, args []string) error {
 	version := args[0]
 
-	vendorFactory, ok := vendors[installVendor]
-	if !ok {
-		return fmt.Errorf("unknown vendor: %s (available: temurin, corretto, zulu)", installVendor)
+	spec, err := jdkversion.ParseSpec(version)
+	if err != nil {
+		return err
+	}
+
+	vendorName := installVendor
+	if spec.Vendor != "" {
+		if installVendor != "" && installVendor != spec.Vendor {
+			return fmt.Errorf("version %s names vendor %s, but --vendor=%s was given", version, spec.Vendor, installVendor)
+		}
+		vendorName = spec.Vendor
 	}
 
-	versionNameFunc := versionNameFuncs[installVendor]
+	if _, ok := vendors[vendorName]; vendorName != "" && !ok {
+		return fmt.Errorf("unknown vendor: %s (available: temurin, corretto, zulu)", vendorName)
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
@@ -104,261 +174,1720 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	}
 
 	reg := registry.New(cfg)
-	vendor := vendorFactory()
+	if installSystem {
+		systemCfg, err := config.LoadSystem()
+		if err != nil {
+			return fmt.Errorf("failed to load system store: %w", err)
+		}
+		reg = registry.NewSystem(systemCfg)
+	}
 
-	installName := versionNameFunc(version)
-	if reg.IsInstalled(installName) {
-		fmt.Printf("Java %s (%s) is already installed\n", version, installName)
-		return nil
+	arch := installArch
+	if spec.Arch != "" {
+		if arch != "" && platform.NormalizeArch(arch) != spec.Arch {
+			return fmt.Errorf("version %s names arch %s, but --arch=%s was given", version, spec.Arch, installArch)
+		}
+		arch = spec.Arch
+	}
+	if arch == "" {
+		arch = cfg.Settings.Arch
 	}
 
-	fmt.Printf("Fetching release info for Java %s from %s...\n", version, installVendor)
-	opts := &provider.Options{Arch: installArch}
-	release, err := vendor.GetRelease(version, opts)
-	if err != nil {
-		return fmt.Errorf("failed to get release info: %w", err)
+	imageType := installImage
+	if spec.ImageType != "" {
+		if imageType != "" && imageType != spec.ImageType {
+			return fmt.Errorf("version %s names image type %s, but --image-type=%s was given", version, spec.ImageType, installImage)
+		}
+		imageType = spec.ImageType
+	}
+
+	libc := installLibC
+	if spec.LibC != "" {
+		if libc != "" && libc != spec.LibC {
+			return fmt.Errorf("version %s names libc %s, but --libc=%s was given", version, spec.LibC, installLibC)
+		}
+		libc = spec.LibC
+	}
+	if libc != "" {
+		if !platform.IsLibC(libc) {
+			return fmt.Errorf("unknown libc: %s (available: glibc, musl)", libc)
+		}
+		if platform.LibC() == "" {
+			return fmt.Errorf("--libc is only supported on Linux")
+		}
+	}
+
+	channel := installChan
+	if spec.Channel != "" {
+		if channel != "" && channel != spec.Channel {
+			return fmt.Errorf("version %s names channel %s, but --channel=%s was given", version, spec.Channel, installChan)
+		}
+		channel = spec.Channel
+	}
+	if channel == "" {
+		channel = provider.ChannelGA
+	}
+	if channel != provider.ChannelGA && channel != provider.ChannelEA {
+		return fmt.Errorf("unknown channel: %s (available: ga, ea)", channel)
+	}
+
+	opts := &provider.Options{Arch: arch, ImageType: imageType, JavaFX: installJavaFX || spec.JavaFX, LibC: libc, Channel: channel}
+
+	var vendor provider.Vendor
+	var major string
+	var release *provider.Release
+	if vendorName != "" {
+		vendor = vendors[vendorName]()
+		major, err = selectMajor(vendorName, channel, spec)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Fetching release info for Java %s from %s...\n", major, vendorName)
+		release, err = findRelease(vendor, major, spec, opts)
+		if err != nil {
+			return err
+		}
+	} else {
+		preference := vendorPreference(cfg)
+		skipped := 0
+		for _, name := range preference {
+			vendor = vendors[name]()
+			major, err = selectMajor(name, channel, spec)
+			if err == nil {
+				fmt.Printf("Fetching release info for Java %s from %s...\n", major, name)
+				release, err = findRelease(vendor, major, spec, opts)
+			}
+			if err == nil {
+				vendorName = name
+				break
+			}
+
+			fmt.Printf("Skipping %s: %v\n", name, err)
+			skipped++
+		}
+
+		if vendorName == "" {
+			return fmt.Errorf("none of %s could provide Java %s", strings.Join(preference, ", "), version)
+		}
+		if skipped > 0 {
+			fmt.Printf("Using %s\n", vendorName)
+		}
 	}
 
 	fmt.Printf("Found: %s\n", release.FullVersion)
+
+	installName, jvm := releaseEntry(vendor, release)
+	if existing, ok := reg.List()[installName]; ok {
+		if !installRedo {
+			fmt.Printf("Java %s (%s) is already installed\n", version, installName)
+			return nil
+		}
+		if existing.System {
+			return fmt.Errorf("%s is in the system store; use --system to reinstall it there", installName)
+		}
+		if existing.Pinned && !installForce {
+			return fmt.Errorf("%s is pinned; use --force to reinstall it anyway", installName)
+		}
+		jvm.Pinned = existing.Pinned
+		fmt.Printf("Reinstalling %s\n", installName)
+	} else if previous, err := reg.Find(vendorName + "@" + major); err == nil {
+		fmt.Printf("Installing alongside %s\n", previous)
+	}
+
+	jvm, err = installRelease(reg, installName, jvm, release)
+	if err != nil {
+		return err
+	}
+
+	if reg.IsSystem() {
+		fmt.Printf("Successfully installed Java %s as %s in the system store\n", version, installName)
+		return nil
+	}
+
+	fmt.Printf("Successfully installed Java %s as %s\n", version, installName)
+	warnLifecycle(installName, jvm)
+
+	if cfg.Global == "" && registry.Runnable(jvm) && provider.IsRuntimeImage(jvm.ImageType) {
+		if err := reg.SetGlobal(installName); err != nil {
+			return fmt.Errorf("failed to set global version: %w", err)
+		}
+		fmt.Printf("Set %s as global default\n", installName)
+	}
+
+	return nil
+}
+
+// releaseEntry returns the installation name and registry entry a release
+// would be installed as.
+func releaseEntry(vendor provider.Vendor, release *provider.Release) (string, config.InstalledJVM) {
+	jvm := config.InstalledJVM{
+		Vendor:    vendor.Name(),
+		Version:   release.FullVersion,
+		Arch:      platform.NormalizeArch(release.Arch),
+		ImageType: release.ImageType,
+		JavaFX:    release.JavaFX,
+		LibC:      release.LibC,
+		Channel:   release.Channel,
+	}
+	if jvm.Arch == "" {
+		jvm.Arch = platform.Arch()
+	}
+
+	return registry.InstallName(registry.InstallBase(vendor.Name(), release.FullVersion), jvm), jvm
+}
+
+// installRelease downloads and unpacks a release, registers it under
+// installName and refreshes the shims. Installs into the system store leave
+// the shims alone, as those belong to each user.
+func installRelease(reg *registry.Registry, installName string, jvm config.InstalledJVM, release *provider.Release) (config.InstalledJVM, error) {
 	fmt.Printf("Downloading from %s...\n", release.DownloadURL)
 
 	dl := downloader.New()
-	jvmsDir, err := paths.JvmsDir()
+	if retries := config.Get().Settings.DownloadRetries; retries != nil {
+		dl.SetRetries(*retries)
+	}
+	jvmsDir, err := reg.JvmsDir()
 	if err != nil {
-		return fmt.Errorf("failed to get jvms directory: %w", err)
+		return jvm, fmt.Errorf("failed to get jvms directory: %w", err)
 	}
 
-	tmpDir := filepath.Join(jvmsDir, ".tmp")
+	// Each run gets its own scratch directory so that parallel installs do
+	// not delete or pick up each other's downloads.
+	if err := os.MkdirAll(jvmsDir, 0755); err != nil {
+		return jvm, fmt.Errorf("failed to create jvms directory: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(jvmsDir, ".tmp-*")
+	if err != nil {
+		return jvm, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
 	result, err := dl.Download(release.DownloadURL, tmpDir, release.FileName, release.Checksum)
 	if err != nil {
-		return fmt.Errorf("download failed: %w", err)
+		return jvm, fmt.Errorf("download failed: %w", err)
 	}
 
 	fmt.Println("Extracting...")
 	ext := extractor.ForFile(release.FileName)
 	extractDir := filepath.Join(tmpDir, "extract")
-	javaHome, err := ext.Extract(result.FilePath, extractDir)
+	var javaHome string
+	if provider.IsRuntimeImage(release.ImageType) {
+		javaHome, err = ext.Extract(result.FilePath, extractDir)
+	} else if err = ext.Unpack(result.FilePath, extractDir); err == nil {
+		javaHome, err = extractor.ImageRoot(extractDir)
+	}
+	if err != nil {
+		return jvm, fmt.Errorf("extraction failed: %w", err)
+	}
+
+	installPath := filepath.Join(jvmsDir, installName)
+	os.RemoveAll(installPath)
+	if err := os.Rename(javaHome, installPath); err != nil {
+		return jvm, fmt.Errorf("failed to move JDK to install path: %w", err)
+	}
+
+	jvm.Path = installPath
+	if err := reg.Add(installName, jvm); err != nil {
+		return jvm, fmt.Errorf("failed to register installation: %w", err)
+	}
+
+	if reg.IsSystem() {
+		return jvm, nil
+	}
+
+	shimMgr := shim.New()
+	if err := shimMgr.CreateShims(); err != nil {
+		fmt.Printf("Warning: failed to create shims: %v\n", err)
+	}
+
+	return jvm, nil
+}
+
+// findRelease returns the newest release of a major that satisfies spec.
+// The latest release is tried first; older patches and builds are only
+// looked up when the spec pins something the latest release does not match.
+func findRelease(vendor provider.Vendor, major string, spec jdkversion.Spec, opts *provider.Options) (*provider.Release, error) {
+	latest, err := vendor.GetRelease(major, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release info: %w", err)
+	}
+
+	if v, err := jdkversion.Parse(latest.FullVersion); err == nil && spec.Matches(v) {
+		return latest, nil
+	}
+
+	releases, err := vendor.ListReleases(major, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s releases: %w", major, err)
+	}
+
+	var best *provider.Release
+	var bestVersion jdkversion.Version
+	for i := range releases {
+		v, err := jdkversion.Parse(releases[i].FullVersion)
+		if err != nil || !spec.Matches(v) {
+			continue
+		}
+		if best == nil || jdkversion.Compare(v, bestVersion) > 0 {
+			best, bestVersion = &releases[i], v
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("Java %s is not available from %s (latest %s release is %s; run 'jvman list %s@%s --all' to see every release)",
+			spec, vendor.Name(), major, latest.FullVersion, vendor.Name(), major)
+	}
+	return best, nil
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list [version]",
+	Short: "List installed and available Java versions",
+	Long:  "List installed and available Java versions.\n\nExamples:\n  jvman list\n  jvman list 21\n  jvman list 21 --all\n  jvman list corretto@17 --all",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runList,
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var spec jdkversion.Spec
+	if len(args) == 1 {
+		spec, err = jdkversion.ParseSpec(args[0])
+		if err != nil {
+			return err
+		}
+	} else if listAll {
+		return fmt.Errorf("--all requires a version, e.g. jvman list 21 --all")
+	}
+
+	reg := registry.New(cfg)
+	installed := reg.List()
+	lifecycles := lifecycle.Load()
+	now := time.Now()
+
+	global, _ := reg.Find(cfg.Global)
+
+	fmt.Println("Installed versions:")
+	if len(installed) == 0 {
+		fmt.Println("  (none)")
+	} else {
+		for _, name := range reg.Names() {
+			jvm := installed[name]
+			marker := "  "
+			if name == global {
+				marker = "* "
+			}
+			status := ""
+			if v, ok := registry.InstalledVersion(name, jvm); ok {
+				if c, ok := lifecycles.Lookup(jvm.Vendor, v.Major()); ok && c.Status(now) == lifecycle.StatusEOL {
+					status = " [end of life]"
+				}
+			}
+			if jvm.Pinned {
+				status += " [pinned]"
+			}
+			if jvm.System {
+				status += " [system]"
+			}
+			if jvm.External {
+				status += " [external]"
+			}
+			fmt.Printf("%s%s (%s)%s\n", marker, name, registry.Describe(jvm), status)
+		}
+	}
+
+	vendorsToList := defaultVendors
+	if listVendor == "" {
+		listVendor = spec.Vendor
+	}
+	if listVendor != "" {
+		if _, ok := vendors[listVendor]; !ok {
+			return fmt.Errorf("unknown vendor: %s", listVendor)
+		}
+		vendorsToList = []string{listVendor}
+	}
+
+	if listAll {
+		major, ok := spec.Major()
+		if !ok {
+			return fmt.Errorf("--all requires a single feature release, e.g. 21 or 21.0")
+		}
+		for _, vendorName := range vendorsToList {
+			listReleases(reg, vendorName, strconv.Itoa(major), spec)
+		}
+		return nil
+	}
+
+	for _, vendorName := range vendorsToList {
+		fmt.Println()
+		fmt.Printf("Available versions (%s):\n", vendorName)
+
+		available, err := availableVersions(vendorName, provider.ChannelGA, listRefresh)
+		if err != nil {
+			fmt.Printf("  Error fetching: %v\n", err)
+			continue
+		}
+
+		lts := ltsMajors(available)
+		for _, rel := range available {
+			if len(args) == 1 {
+				major, err := strconv.Atoi(rel.Version)
+				if err != nil || !spec.AllowsMajor(major) {
+					continue
+				}
+				if spec.Kind == jdkversion.KindLTS && !lts[rel.Version] {
+					continue
+				}
+			}
+
+			var labels []string
+			if lts[rel.Version] {
+				labels = append(labels, "LTS")
+			}
+			if major, err := strconv.Atoi(rel.Version); err == nil {
+				if c, ok := lifecycles.Lookup(vendorName, major); ok {
+					if summary := c.Summary(now); summary != "" {
+						labels = append(labels, summary)
+					}
+				}
+			}
+
+			status := ""
+			if len(labels) > 0 {
+				status = " (" + strings.Join(labels, ", ") + ")"
+			}
+			if _, err := reg.Find(vendorName + "@" + rel.Version); err == nil {
+				status += " [installed]"
+			}
+			fmt.Printf("  %s%s\n", rel.Version, status)
+		}
+	}
+
+	return nil
+}
+
+// listReleases prints every build of one feature release from a vendor.
+func listReleases(reg *registry.Registry, vendorName, major string, spec jdkversion.Spec) {
+	fmt.Println()
+	fmt.Printf("Available %s releases (%s):\n", major, vendorName)
+
+	releases, err := vendorReleases(vendorName, major, listRefresh)
+	if err != nil {
+		fmt.Printf("  Error fetching: %v\n", err)
+		return
+	}
+
+	installedVersions := make(map[string]bool)
+	for _, name := range reg.Names() {
+		jvm := reg.List()[name]
+		if jvm.Vendor != vendorName {
+			continue
+		}
+		if v, ok := registry.InstalledVersion(name, jvm); ok {
+			installedVersions[v.String()] = true
+		}
+	}
+
+	isLTS := false
+	if available, err := availableVersions(vendorName, provider.ChannelGA, false); err == nil {
+		isLTS = ltsMajors(available)[major]
+	} else if m, err := strconv.Atoi(major); err == nil {
+		isLTS = jdkversion.IsLTSMajor(m)
+	}
+
+	lts := ""
+	if isLTS {
+		lts = "LTS"
+	}
+
+	for _, rel := range releases {
+		v, err := jdkversion.Parse(rel.FullVersion)
+		if err != nil || !spec.Matches(v) {
+			continue
+		}
+
+		date := "-"
+		if !rel.ReleaseDate.IsZero() {
+			date = rel.ReleaseDate.Format("2006-01-02")
+		}
+		size := "-"
+		if rel.Size > 0 {
+			size = formatSize(rel.Size)
+		}
+
+		status := ""
+		if installedVersions[v.String()] {
+			status = " [installed]"
+		}
+		fmt.Printf("  %-14s %-10s %9s  %s%s\n", v, date, size, lts, status)
+	}
+}
+
+// vendorReleases returns every build of a feature release, cached per vendor
+// and major.
+func vendorReleases(vendorName, major string, refresh bool) ([]provider.Release, error) {
+	releaseCache, err := newCache()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cache: %w", err)
+	}
+
+	cacheKey := fmt.Sprintf("%s-%s-releases", vendorName, major)
+	if !refresh {
+		if releases, ok := releaseCache.GetVersions(cacheKey); ok {
+			return releases, nil
+		}
+	}
+
+	vendor := vendors[vendorName]()
+	releases, err := vendor.ListReleases(major, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	releaseCache.SetVersions(cacheKey, releases)
+	return releases, nil
+}
+
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+func availableVersions(vendorName, channel string, refresh bool) ([]provider.Release, error) {
+	versionCache, err := newCache()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cache: %w", err)
+	}
+
+	cacheKey := vendorName
+	if channel != provider.ChannelGA {
+		cacheKey += "-" + channel
+	}
+
+	if !refresh {
+		if available, ok := versionCache.GetVersions(cacheKey); ok {
+			return available, nil
+		}
+	}
+
+	vendor := vendors[vendorName]()
+	available, err := vendor.ListAvailableVersions(&provider.Options{Channel: channel})
+	if err != nil {
+		return nil, err
+	}
+
+	versionCache.SetVersions(cacheKey, available)
+	return available, nil
+}
+
+// warnLifecycle prints a warning when an installation is past or close to
+// the end of its vendor's support.
+func warnLifecycle(name string, jvm config.InstalledJVM) {
+	v, ok := registry.InstalledVersion(name, jvm)
+	if !ok {
+		return
+	}
+	if warning := lifecycle.Load().Warning(jvm.Vendor, v.Major(), time.Now()); warning != "" {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+}
+
+// newCache opens the version cache with the configured TTL.
+func newCache() (*cache.Cache, error) {
+	c, err := cache.New()
+	if err != nil {
+		return nil, err
+	}
+	if ttl, err := time.ParseDuration(config.Get().Settings.CacheTTL); err == nil {
+		c.SetTTL(ttl)
+	}
+	return c, nil
+}
+
+// ltsMajors returns the feature releases a vendor marks as LTS. Lists cached
+// before vendors reported this fall back to OpenJDK's LTS cadence.
+func ltsMajors(available []provider.Release) map[string]bool {
+	lts := make(map[string]bool)
+	for _, rel := range available {
+		if rel.LTS {
+			lts[rel.Version] = true
+		}
+	}
+	if len(lts) > 0 {
+		return lts
+	}
+
+	for _, rel := range available {
+		if major, err := strconv.Atoi(rel.Version); err == nil && jdkversion.IsLTSMajor(major) {
+			lts[rel.Version] = true
+		}
+	}
+	return lts
+}
+
+// selectMajor picks the feature release to install for a spec: the one it
+// names, or the highest available one it allows.
+func selectMajor(vendorName, channel string, spec jdkversion.Spec) (string, error) {
+	if major, ok := spec.Major(); ok {
+		return strconv.Itoa(major), nil
+	}
+
+	available, err := availableVersions(vendorName, channel, false)
 	if err != nil {
-		os.RemoveAll(tmpDir)
-		return fmt.Errorf("extraction failed: %w", err)
+		return "", fmt.Errorf("failed to list available versions: %w", err)
+	}
+
+	lts := ltsMajors(available)
+
+	best := -1
+	for _, rel := range available {
+		major, err := strconv.Atoi(rel.Version)
+		if err != nil {
+			continue
+		}
+		allowed := spec.AllowsMajor(major)
+		if spec.Kind == jdkversion.KindLTS {
+			allowed = lts[rel.Version]
+		}
+		if allowed && major > best {
+			best = major
+		}
+	}
+
+	if best < 0 {
+		return "", fmt.Errorf("no %s release matches %s", vendorName, spec)
+	}
+	return strconv.Itoa(best), nil
+}
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Show installations with a newer patch release available",
+	Args:  cobra.NoArgs,
+	RunE:  runOutdated,
+}
+
+func runOutdated(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	reg := registry.New(cfg)
+	installed := reg.List()
+	if len(installed) == 0 {
+		fmt.Println("No Java versions installed")
+		return nil
+	}
+
+	outdated := 0
+	failed := 0
+	fmt.Printf("  %-32s %-16s %s\n", "Installation", "Installed", "Latest")
+	for _, name := range reg.Names() {
+		if _, ok := vendors[installed[name].Vendor]; !ok && installed[name].External {
+			// jvman has no releases to compare these with.
+			continue
+		}
+
+		u, err := checkUpdate(name, installed[name])
+		if err != nil {
+			fmt.Printf("  %-32s Error: %v\n", name, err)
+			failed++
+			continue
+		}
+
+		status := ""
+		if u.available() {
+			status = "update available"
+			if u.jvm.Pinned {
+				status += " (pinned)"
+			}
+			if u.jvm.System {
+				status += " (system)"
+			}
+			if u.jvm.External {
+				status += " (external)"
+			}
+			outdated++
+		}
+		fmt.Printf("  %-32s %-16s %-16s %s\n", name, u.current, u.latestVersion, status)
+	}
+
+	if outdated > 0 {
+		fmt.Println()
+		fmt.Println("Run 'jvman update <version>' or 'jvman update --all' to update.")
+	} else if failed == 0 {
+		fmt.Println()
+		fmt.Println("All installations are up to date")
+	}
+	return nil
+}
+
+var updateCmd = &cobra.Command{
+	Use:   "update [version]",
+	Short: "Update installations to the latest patch release",
+	Long:  "Install the latest patch release for an installation's vendor and major, move the global default, local overrides and .jvman files over to it, and remove the old installation once the new one passes a smoke test.\n\nExamples:\n  jvman update temurin-21\n  jvman update --all\n  jvman update 17 --keep",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runUpdate,
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	if updateAll == (len(args) == 1) {
+		return fmt.Errorf("specify either an installation or --all")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	reg := registry.New(cfg)
+
+	names := reg.Names()
+	if !updateAll {
+		name, err := resolveInstalledName(reg, args[0])
+		if err != nil {
+			return err
+		}
+		names = []string{name}
+	}
+
+	failed := 0
+	updated := 0
+	for _, name := range names {
+		jvm, ok := reg.List()[name]
+		if !ok {
+			// Already replaced while updating another installation.
+			continue
+		}
+
+		if jvm.External {
+			if !updateAll {
+				fmt.Printf("%s is managed outside jvman, not updating it\n", name)
+			}
+			continue
+		}
+
+		u, err := checkUpdate(name, jvm)
+		if err != nil {
+			fmt.Printf("Error checking %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		if !u.available() {
+			if !updateAll {
+				fmt.Printf("%s is up to date\n", name)
+			}
+			continue
+		}
+		if jvm.Pinned {
+			fmt.Printf("%s is pinned, not updating it to %s\n", name, u.latestVersion)
+			continue
+		}
+		if jvm.System {
+			fmt.Printf("%s is in the system store, not updating it to %s\n", name, u.latestVersion)
+			continue
+		}
+
+		if err := applyUpdate(reg, u); err != nil {
+			fmt.Printf("Error updating %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		updated++
+	}
+
+	if updateAll && updated == 0 && failed == 0 {
+		fmt.Println("All installations are up to date")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d update(s) failed", failed)
+	}
+	return nil
+}
+
+// pendingUpdate pairs an installation with the newest release for its vendor,
+// major and variant.
+type pendingUpdate struct {
+	name          string
+	jvm           config.InstalledJVM
+	current       jdkversion.Version
+	latest        *provider.Release
+	latestVersion jdkversion.Version
+}
+
+func (u pendingUpdate) available() bool {
+	latest := u.latestVersion
+	// Zulu installs used to be recorded without their build number.
+	if u.current.Build == 0 {
+		latest.Build = 0
+	}
+	return jdkversion.Compare(latest, u.current) > 0
+}
+
+func checkUpdate(name string, jvm config.InstalledJVM) (pendingUpdate, error) {
+	u := pendingUpdate{name: name, jvm: jvm}
+
+	current, ok := registry.InstalledVersion(name, jvm)
+	if !ok {
+		return u, fmt.Errorf("cannot determine the installed version")
+	}
+	u.current = current
+
+	vendorFactory, ok := vendors[jvm.Vendor]
+	if !ok {
+		return u, fmt.Errorf("unknown vendor: %s", jvm.Vendor)
+	}
+
+	opts := &provider.Options{
+		Arch:      registry.Arch(jvm),
+		ImageType: registry.ImageType(jvm),
+		JavaFX:    jvm.JavaFX,
+		LibC:      jvm.LibC,
+		Channel:   registry.Channel(jvm),
+	}
+	latest, err := vendorFactory().GetRelease(strconv.Itoa(current.Major()), opts)
+	if err != nil {
+		return u, err
+	}
+
+	latestVersion, err := jdkversion.Parse(latest.FullVersion)
+	if err != nil {
+		return u, fmt.Errorf("unrecognised release version %s", latest.FullVersion)
+	}
+
+	u.latest = latest
+	u.latestVersion = latestVersion
+	return u, nil
+}
+
+// applyUpdate installs the newer release next to the old one and, once it
+// passes a smoke test, moves references over and removes the old one.
+func applyUpdate(reg *registry.Registry, u pendingUpdate) error {
+	vendor := vendors[u.jvm.Vendor]()
+	newName, jvm := releaseEntry(vendor, u.latest)
+
+	fmt.Printf("Updating %s to %s...\n", u.name, newName)
+
+	installed := false
+	if existing, ok := reg.List()[newName]; ok {
+		jvm = existing
+	} else {
+		var err error
+		if jvm, err = installRelease(reg, newName, jvm, u.latest); err != nil {
+			return err
+		}
+		installed = true
+	}
+
+	if err := smokeTest(jvm); err != nil {
+		if installed {
+			reg.Remove(newName)
+		}
+		return fmt.Errorf("%s failed its smoke test, keeping %s: %w", newName, u.name, err)
+	}
+
+	rewritten, err := reg.Repoint(u.name, newName)
+	if err != nil {
+		return fmt.Errorf("failed to move references to %s: %w", newName, err)
+	}
+	for _, path := range rewritten {
+		fmt.Printf("Updated %s\n", path)
+	}
+
+	if !updateKeep {
+		if err := reg.Remove(u.name); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", u.name, err)
+		}
+	}
+
+	fmt.Printf("Updated %s to %s\n", u.name, newName)
+	return nil
+}
+
+// smokeTest checks that an installation's java starts. Images without a
+// runtime and builds this machine cannot run are not tested.
+func smokeTest(jvm config.InstalledJVM) error {
+	if !provider.IsRuntimeImage(registry.ImageType(jvm)) || !registry.Runnable(jvm) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, paths.JavaBinaryPath(jvm.Path), "-version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("java -version failed: %w\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+var globalCmd = &cobra.Command{
+	Use:   "global <version>",
+	Short: "Set the global default Java version",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runGlobal,
+}
+
+func runGlobal(cmd *cobra.Command, args []string) error {
+	version := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	reg := registry.New(cfg)
+
+	name, err := resolveInstalledName(reg, version)
+	if err != nil {
+		return err
+	}
+
+	if err := checkRunnable(reg, name, globalForce); err != nil {
+		return err
+	}
+
+	target := selectionTarget(reg, version, name)
+	if err := reg.SetGlobal(target); err != nil {
+		return fmt.Errorf("failed to set global version: %w", err)
+	}
+
+	shimMgr := shim.New()
+	if err := shimMgr.CreateShims(); err != nil {
+		fmt.Printf("Warning: failed to update shims: %v\n", err)
+	}
+
+	fmt.Printf("Global Java version set to %s\n", describeTarget(target, name))
+	warnLifecycle(name, reg.List()[name])
+	return nil
+}
+
+var useCmd = &cobra.Command{
+	Use:   "use <version>",
+	Short: "Set the Java version for the current directory",
+	Long:  "Creates a .jvman file in the current directory to specify the Java version",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runUse,
+}
+
+func runUse(cmd *cobra.Command, args []string) error {
+	version := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	reg := registry.New(cfg)
+
+	name, err := resolveInstalledName(reg, version)
+	if err != nil {
+		return err
+	}
+
+	if err := checkRunnable(reg, name, useForce); err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	localFile := filepath.Join(cwd, paths.LocalVersionFile())
+	target := selectionTarget(reg, version, name)
+	if err := os.WriteFile(localFile, []byte(target+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to create .jvman file: %w", err)
+	}
+
+	if err := reg.TrackLocalFile(localFile); err != nil {
+		fmt.Printf("Warning: failed to track %s: %v\n", localFile, err)
+	}
+
+	fmt.Printf("Created .jvman file with version %s\n", describeTarget(target, name))
+	warnLifecycle(name, reg.List()[name])
+	return nil
+}
+
+var removeCmd = &cobra.Command{
+	Use:   "remove <version>",
+	Short: "Remove an installed Java version",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRemove,
+}
+
+func runRemove(cmd *cobra.Command, args []string) error {
+	version := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	reg := registry.New(cfg)
+	if removeSystem {
+		systemCfg, err := config.LoadSystem()
+		if err != nil {
+			return fmt.Errorf("failed to load system store: %w", err)
+		}
+		reg = registry.NewSystem(systemCfg)
+	}
+
+	name, err := resolveInstalledName(reg, version)
+	if err != nil {
+		return err
+	}
+
+	jvm := reg.List()[name]
+	var aliases []string
+	for _, alias := range reg.AliasNames() {
+		if target, _ := reg.Alias(alias); target == name {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	remove := reg.Remove
+	if removeForce {
+		remove = reg.ForceRemove
+	}
+	if err := remove(name); err != nil {
+		if errors.Is(err, registry.ErrPinned) {
+			return fmt.Errorf("%s is pinned; run 'jvman unpin %s' or use --force", name, name)
+		}
+		if errors.Is(err, registry.ErrSystem) {
+			return fmt.Errorf("%s is in the system store; use --system to remove it from there", name)
+		}
+		return fmt.Errorf("failed to remove: %w", err)
+	}
+
+	if jvm.External {
+		fmt.Printf("Unregistered %s; %s was left in place\n", name, jvm.Path)
+	} else {
+		fmt.Printf("Removed %s\n", name)
+	}
+	for _, alias := range aliases {
+		fmt.Printf("Removed alias %s\n", alias)
+	}
+	return nil
+}
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old and unused Java versions",
+	Long:  "Remove installations that are not used by the global default, a local override or a .jvman file created by 'jvman use'. With --keep, the newest N patches of each vendor, major and variant are kept even when unused; --unreferenced removes every unused installation and is the same as --keep 0. Installations in use and pinned installations are always kept.\n\nExamples:\n  jvman prune --keep 1\n  jvman prune --unreferenced --dry-run",
+	Args:  cobra.NoArgs,
+	RunE:  runPrune,
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	if !cmd.Flags().Changed("keep") && !pruneUnref {
+		return fmt.Errorf("specify --keep N or --unreferenced")
+	}
+	if pruneKeep < 0 {
+		return fmt.Errorf("--keep must not be negative")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	reg := registry.New(cfg)
+
+	names := reg.Prunable(pruneKeep)
+	if len(names) == 0 {
+		fmt.Println("Nothing to prune")
+		return nil
+	}
+
+	var reclaimed int64
+	for _, name := range names {
+		size := dirSize(reg.List()[name].Path)
+
+		if pruneDryRun {
+			fmt.Printf("Would remove %s (%s)\n", name, formatSize(size))
+			reclaimed += size
+			continue
+		}
+
+		if err := reg.Remove(name); err != nil {
+			fmt.Printf("Error removing %s: %v\n", name, err)
+			continue
+		}
+		fmt.Printf("Removed %s (%s)\n", name, formatSize(size))
+		reclaimed += size
+	}
+
+	if pruneDryRun {
+		fmt.Printf("Would reclaim %s\n", formatSize(reclaimed))
+	} else {
+		fmt.Printf("Reclaimed %s\n", formatSize(reclaimed))
+	}
+	return nil
+}
+
+// dirSize returns the total size of the files under dir.
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+var relocateCmd = &cobra.Command{
+	Use:   "relocate <dir>",
+	Short: "Move installed JDKs to another directory",
+	Long:  "Move every installation to <dir>, such as a larger disk, and install there from now on. A JDK that runs before the move is checked to still run before its old copy is deleted.\n\nExamples:\n  jvman relocate /mnt/data/jdks\n  jvman relocate ~/.jvman/jvms",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRelocate,
+}
+
+func runRelocate(cmd *cobra.Command, args []string) error {
+	newDir, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid directory: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	oldDir, err := paths.JvmsDir()
+	if err != nil {
+		return fmt.Errorf("failed to get jvms directory: %w", err)
+	}
+
+	if err := os.MkdirAll(newDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", newDir, err)
+	}
+
+	reg := registry.New(cfg)
+	var names []string
+	for _, name := range reg.Names() {
+		jvm := cfg.Installed[name]
+		if !jvm.System && !jvm.External && filepath.Dir(jvm.Path) != newDir {
+			names = append(names, name)
+		}
+	}
+
+	moved := 0
+	for i, name := range names {
+		jvm := cfg.Installed[name]
+		if _, err := os.Stat(jvm.Path); err != nil {
+			fmt.Printf("[%d/%d] Skipping %s: %s does not exist\n", i+1, len(names), name, jvm.Path)
+			continue
+		}
+
+		fmt.Printf("[%d/%d] Moving %s...\n", i+1, len(names), name)
+		if err := relocateJVM(reg, name, jvm, filepath.Join(newDir, name)); err != nil {
+			return fmt.Errorf("failed to move %s: %w", name, err)
+		}
+		moved++
+	}
+
+	storeDir := newDir
+	if defaultDir, err := paths.DefaultJvmsDir(); err == nil && defaultDir == newDir {
+		storeDir = ""
+	}
+	_, err = config.Update(func(cfg *config.Config) error {
+		cfg.Settings.StoreDir = storeDir
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	shimMgr := shim.New()
+	if err := shimMgr.CreateShims(); err != nil {
+		fmt.Printf("Warning: failed to create shims: %v\n", err)
+	}
+
+	if oldDir != newDir {
+		// Only succeeds once nothing else is left in it.
+		os.Remove(oldDir)
+	}
+
+	fmt.Printf("Moved %d installation(s); new installations go to %s\n", moved, newDir)
+	return nil
+}
+
+// relocateJVM moves an installation to dest, renaming it where possible and
+// copying it to another filesystem otherwise. A copy only replaces the
+// original once it passes the same smoke test the original did.
+func relocateJVM(reg *registry.Registry, name string, jvm config.InstalledJVM, dest string) error {
+	if _, err := os.Lstat(dest); err == nil {
+		return fmt.Errorf("%s already exists", dest)
+	}
+
+	src := jvm.Path
+	verify := smokeTest(jvm) == nil
+	moved := jvm
+	moved.Path = dest
+
+	if err := os.Rename(src, dest); err == nil {
+		if verify {
+			if err := smokeTest(moved); err != nil {
+				os.Rename(dest, src)
+				return err
+			}
+		}
+		if err := reg.SetPath(name, dest); err != nil {
+			os.Rename(dest, src)
+			return fmt.Errorf("failed to update registry: %w", err)
+		}
+		return nil
+	}
+
+	bar := progressbar.NewOptions64(
+		dirSize(src),
+		progressbar.OptionSetDescription("Copying"),
+		progressbar.OptionSetWriter(os.Stderr),
+		progressbar.OptionShowBytes(true),
+		progressbar.OptionSetWidth(40),
+		progressbar.OptionThrottle(65*time.Millisecond),
+		progressbar.OptionOnCompletion(func() {
+			fmt.Fprint(os.Stderr, "\n")
+		}),
+	)
+	if err := fsutil.CopyTree(src, dest, bar); err != nil {
+		os.RemoveAll(dest)
+		return fmt.Errorf("failed to copy: %w", err)
+	}
+	bar.Finish()
+
+	if verify {
+		if err := smokeTest(moved); err != nil {
+			os.RemoveAll(dest)
+			return err
+		}
+	}
+	if err := reg.SetPath(name, dest); err != nil {
+		os.RemoveAll(dest)
+		return fmt.Errorf("failed to update registry: %w", err)
+	}
+
+	if err := os.RemoveAll(src); err != nil {
+		fmt.Printf("Warning: failed to remove %s: %v\n", src, err)
+	}
+	return nil
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import [dir]...",
+	Short: "Register JDKs that were installed without jvman",
+	Long:  "Register existing Java homes so that they can be selected like installed versions. jvman reads each JDK's release file for its vendor, version and architecture, and never deletes imported JDKs: 'jvman remove' only unregisters them.\n\nWith --scan, the directories where JDKs are commonly installed (such as /usr/lib/jvm) are searched, as well as any given ones.\n\nExamples:\n  jvman import --scan\n  jvman import --scan ~/jdks\n  jvman import /usr/lib/jvm/java-17-openjdk-amd64",
+	RunE:  runImport,
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	if !importScan && len(args) == 0 {
+		return fmt.Errorf("specify the JDKs to import or use --scan")
+	}
+
+	// Relative paths would only resolve from the current directory.
+	dirs := make([]string, len(args))
+	for i, arg := range args {
+		dir, err := filepath.Abs(arg)
+		if err != nil {
+			return fmt.Errorf("invalid path: %w", err)
+		}
+		dirs[i] = dir
+	}
+
+	var found []discover.JDK
+	if importScan {
+		found = discover.Scan(append(discover.DefaultDirs(), dirs...))
+	} else {
+		for _, dir := range dirs {
+			home, ok := extractor.JavaHome(dir)
+			if !ok {
+				return fmt.Errorf("%s is not a Java home: bin/java not found", dir)
+			}
+			jdk, err := discover.Inspect(home)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", home, err)
+			}
+			found = append(found, jdk)
+		}
+	}
+
+	if len(found) == 0 {
+		fmt.Println("No JDKs found")
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	reg := registry.New(cfg)
+	imported := 0
+	for _, jdk := range found {
+		if existing, ok := reg.NameOf(jdk.Home); ok {
+			fmt.Printf("Skipping %s: already registered as %s\n", jdk.Home, existing)
+			continue
+		}
+
+		name, jvm := externalEntry(jdk)
+		if reg.IsInstalled(name) {
+			fmt.Printf("Skipping %s: the name %s is already in use\n", jdk.Home, name)
+			continue
+		}
+
+		if importDryRun {
+			fmt.Printf("Would import %s from %s\n", name, jdk.Home)
+			continue
+		}
+		if err := reg.Add(name, jvm); err != nil {
+			return fmt.Errorf("failed to register %s: %w", name, err)
+		}
+		fmt.Printf("Imported %s from %s\n", name, jdk.Home)
+		imported++
+	}
+
+	if imported > 0 {
+		fmt.Printf("Imported %d JDK(s)\n", imported)
+	}
+	return nil
+}
+
+// externalEntry returns the installation name and registry entry for a JDK
+// that jvman did not install, named like one it would have installed.
+func externalEntry(jdk discover.JDK) (string, config.InstalledJVM) {
+	jvm := config.InstalledJVM{
+		Path:      jdk.Home,
+		Vendor:    jdk.Vendor,
+		Version:   jdk.Version,
+		Arch:      jdk.Arch,
+		ImageType: jdk.ImageType,
+		LibC:      jdk.LibC,
+		Channel:   jdk.Channel,
+		External:  true,
 	}
 
-	installPath, err := paths.JvmPath(installName)
+	return registry.InstallName(registry.InstallBase(jdk.Vendor, jdk.Version), jvm), jvm
+}
+
+var linkCmd = &cobra.Command{
+	Use:   "link <name> <path>",
+	Short: "Register a local JDK directory under a name",
+	Long:  "Register a JDK that jvman did not install, such as an OpenJDK built from source or an unpacked vendor archive, so that it can be used with global, use, exec and the shims. 'jvman remove <name>' unregisters it without deleting it.\n\nExamples:\n  jvman link jdk-dev ~/src/jdk/build/linux-x86_64-server-release/images/jdk\n  jvman link graal ~/Downloads/graalvm-jdk-21",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runLink,
+}
+
+func runLink(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	dir, err := filepath.Abs(args[1])
 	if err != nil {
-		os.RemoveAll(tmpDir)
-		return fmt.Errorf("failed to get install path: %w", err)
+		return fmt.Errorf("invalid path: %w", err)
 	}
 
-	os.RemoveAll(installPath)
-	if err := os.Rename(javaHome, installPath); err != nil {
-		os.RemoveAll(tmpDir)
-		return fmt.Errorf("failed to move JDK to install path: %w", err)
+	home, ok := extractor.JavaHome(dir)
+	if !ok {
+		return fmt.Errorf("%s is not a Java home: bin/java not found", dir)
 	}
 
-	os.RemoveAll(tmpDir)
-
-	if err := reg.Add(installName, installPath, vendor.Name()); err != nil {
-		return fmt.Errorf("failed to register installation: %w", err)
+	// Builds without a release file are still usable, just not described.
+	jvm := config.InstalledJVM{Path: home, Vendor: "local", Arch: platform.Arch()}
+	if jdk, err := discover.Inspect(home); err == nil {
+		_, jvm = externalEntry(jdk)
 	}
 
-	shimMgr := shim.New()
-	if err := shimMgr.CreateShims(); err != nil {
-		fmt.Printf("Warning: failed to create shims: %v\n", err)
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	fmt.Printf("Successfully installed Java %s as %s\n", version, installName)
+	reg := registry.New(cfg)
+	if existing, ok := reg.NameOf(home); ok {
+		fmt.Printf("Note: %s is also registered as %s\n", home, existing)
+	}
 
-	if cfg.Global == "" {
-		if err := reg.SetGlobal(installName); err != nil {
-			return fmt.Errorf("failed to set global version: %w", err)
-		}
-		fmt.Printf("Set %s as global default\n", installName)
+	if err := reg.Link(name, jvm); err != nil {
+		return err
 	}
 
+	fmt.Printf("Linked %s to %s (%s)\n", name, home, registry.Describe(jvm))
 	return nil
 }
 
-var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List installed and available Java versions",
-	RunE:  runList,
+var pinCmd = &cobra.Command{
+	Use:   "pin <version>",
+	Short: "Protect an installation from update, prune and removal",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPinned(args[0], true)
+	},
 }
 
-func runList(cmd *cobra.Command, args []string) error {
+var unpinCmd = &cobra.Command{
+	Use:   "unpin <version>",
+	Short: "Remove the protection added by pin",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPinned(args[0], false)
+	},
+}
+
+func setPinned(version string, pinned bool) error {
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
 	reg := registry.New(cfg)
-	installed := reg.List()
 
-	fmt.Println("Installed versions:")
-	if len(installed) == 0 {
-		fmt.Println("  (none)")
-	} else {
-		for name, jvm := range installed {
-			marker := "  "
-			if name == cfg.Global {
-				marker = "* "
-			}
-			fmt.Printf("%s%s (%s)\n", marker, name, jvm.Vendor)
+	name, err := resolveInstalledName(reg, version)
+	if err != nil {
+		return err
+	}
+
+	if err := reg.SetPinned(name, pinned); err != nil {
+		if errors.Is(err, registry.ErrSystem) {
+			return fmt.Errorf("%s is in the system store and cannot be pinned or unpinned", name)
 		}
+		return err
 	}
 
-	versionCache, err := cache.New()
-	if err != nil {
-		return fmt.Errorf("failed to initialize cache: %w", err)
+	if pinned {
+		fmt.Printf("Pinned %s\n", name)
+	} else {
+		fmt.Printf("Unpinned %s\n", name)
 	}
+	return nil
+}
 
-	vendorsToList := []string{"temurin", "corretto", "zulu"}
-	if listVendor != "" {
-		if _, ok := vendors[listVendor]; !ok {
-			return fmt.Errorf("unknown vendor: %s", listVendor)
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage names for installations",
+	Long:  "Aliases are names such as \"build\" or \"runtime\" that stand for an installation or version spec. They are accepted wherever a version is, and global, use and .jvman files that name an alias follow it when it is changed.",
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <alias> <version>",
+	Short: "Create or change an alias",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
 		}
-		vendorsToList = []string{listVendor}
-	}
 
-	for _, vendorName := range vendorsToList {
-		fmt.Println()
-		fmt.Printf("Available versions (%s):\n", vendorName)
+		reg := registry.New(cfg)
+		if err := reg.SetAlias(args[0], args[1]); err != nil {
+			return err
+		}
 
-		versionNameFunc := versionNameFuncs[vendorName]
+		name, _ := reg.Find(args[0])
+		fmt.Printf("Alias %s now points to %s\n", args[0], describeTarget(args[1], name))
+		return nil
+	},
+}
 
-		var available []provider.Release
-		var fromCache bool
+var aliasRemoveCmd = &cobra.Command{
+	Use:     "rm <alias>",
+	Aliases: []string{"remove"},
+	Short:   "Remove an alias",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
 
-		if !listRefresh {
-			available, fromCache = versionCache.GetVersions(vendorName)
+		reg := registry.New(cfg)
+		if err := reg.RemoveAlias(args[0]); err != nil {
+			return err
 		}
 
-		if !fromCache {
-			vendorFactory := vendors[vendorName]
-			vendor := vendorFactory()
+		fmt.Printf("Removed alias %s\n", args[0])
+		if cfg.Global == args[0] {
+			fmt.Printf("Warning: the global default still refers to %s\n", args[0])
+		}
+		return nil
+	},
+}
 
-			var fetchErr error
-			available, fetchErr = vendor.ListAvailableVersions()
-			if fetchErr != nil {
-				fmt.Printf("  Error fetching: %v\n", fetchErr)
-				continue
-			}
+var aliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List aliases",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
 
-			versionCache.SetVersions(vendorName, available)
+		reg := registry.New(cfg)
+		aliases := reg.AliasNames()
+		if len(aliases) == 0 {
+			fmt.Println("No aliases defined")
+			return nil
 		}
 
-		for _, rel := range available {
-			installedName := versionNameFunc(rel.Version)
-			status := ""
-			if reg.IsInstalled(installedName) {
-				status = " [installed]"
+		for _, alias := range aliases {
+			target, _ := reg.Alias(alias)
+			name, err := reg.Find(target)
+			if err != nil {
+				name = "not installed"
 			}
-			fmt.Printf("  %s%s\n", rel.Version, status)
+			fmt.Printf("  %s -> %s\n", alias, describeTarget(target, name))
 		}
-	}
-
-	return nil
+		return nil
+	},
 }
 
-var globalCmd = &cobra.Command{
-	Use:   "global <version>",
-	Short: "Set the global default Java version",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runGlobal,
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+	aliasCmd.AddCommand(aliasListCmd)
 }
 
-func runGlobal(cmd *cobra.Command, args []string) error {
-	version := args[0]
-
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+func validateVendors(names []string) error {
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if _, ok := vendors[name]; !ok {
+			return fmt.Errorf("unknown vendor: %s (available: %s)", name, strings.Join(defaultVendors, ", "))
+		}
+		if seen[name] {
+			return fmt.Errorf("vendor %s is listed more than once", name)
+		}
+		seen[name] = true
 	}
+	return nil
+}
 
-	reg := registry.New(cfg)
-
-	name := resolveInstalledName(reg, version)
-	if name == "" {
-		return fmt.Errorf("Java version %s is not installed. Run 'jvman install %s' first", version, version)
-	}
+// setting describes a key accepted by 'jvman config'. get returns "" when the
+// key is unset.
+type setting struct {
+	usage string
+	def   func() string
+	get   func(s *config.Settings) string
+	set   func(s *config.Settings, value string) error
+	unset func(s *config.Settings)
+}
 
-	if err := reg.SetGlobal(name); err != nil {
-		return fmt.Errorf("failed to set global version: %w", err)
-	}
+var settingNames = []string{"vendors", "arch", "cache-ttl", "download-retries"}
+
+var settings = map[string]setting{
+	"vendors": {
+		usage: "Comma-separated order in which install tries vendors",
+		def:   func() string { return strings.Join(defaultVendors, ",") },
+		get:   func(s *config.Settings) string { return strings.Join(s.Vendors, ",") },
+		set: func(s *config.Settings, value string) error {
+			var names []string
+			for _, name := range strings.Split(value, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					names = append(names, name)
+				}
+			}
+			if len(names) == 0 {
+				return fmt.Errorf("at least one vendor is required")
+			}
+			if err := validateVendors(names); err != nil {
+				return err
+			}
+			s.Vendors = names
+			return nil
+		},
+		unset: func(s *config.Settings) { s.Vendors = nil },
+	},
+	"arch": {
+		usage: "Architecture to install when none is given",
+		def:   platform.Arch,
+		get:   func(s *config.Settings) string { return s.Arch },
+		set: func(s *config.Settings, value string) error {
+			if !platform.IsArch(value) {
+				return fmt.Errorf("unknown architecture: %s", value)
+			}
+			s.Arch = platform.NormalizeArch(value)
+			return nil
+		},
+		unset: func(s *config.Settings) { s.Arch = "" },
+	},
+	"cache-ttl": {
+		usage: "How long version lists are cached (e.g. 30m, 6h)",
+		def:   cache.DefaultTTL.String,
+		get:   func(s *config.Settings) string { return s.CacheTTL },
+		set: func(s *config.Settings, value string) error {
+			ttl, err := time.ParseDuration(value)
+			if err != nil || ttl < 0 {
+				return fmt.Errorf("invalid duration: %s (use e.g. 30m or 6h)", value)
+			}
+			s.CacheTTL = ttl.String()
+			return nil
+		},
+		unset: func(s *config.Settings) { s.CacheTTL = "" },
+	},
+	"download-retries": {
+		usage: "How often a failed download request is retried",
+		def:   func() string { return strconv.Itoa(downloader.DefaultRetries) },
+		get: func(s *config.Settings) string {
+			if s.DownloadRetries == nil {
+				return ""
+			}
+			return strconv.Itoa(*s.DownloadRetries)
+		},
+		set: func(s *config.Settings, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || n > 20 {
+				return fmt.Errorf("invalid retry count: %s (use 0-20)", value)
+			}
+			s.DownloadRetries = &n
+			return nil
+		},
+		unset: func(s *config.Settings) { s.DownloadRetries = nil },
+	},
+}
 
-	shimMgr := shim.New()
-	if err := shimMgr.CreateShims(); err != nil {
-		fmt.Printf("Warning: failed to update shims: %v\n", err)
+func lookupSetting(key string) (setting, error) {
+	st, ok := settings[key]
+	if !ok {
+		return setting{}, fmt.Errorf("unknown setting: %s (available: %s)", key, strings.Join(settingNames, ", "))
 	}
-
-	fmt.Printf("Global Java version set to %s\n", name)
-	return nil
+	return st, nil
 }
 
-var useCmd = &cobra.Command{
-	Use:   "use <version>",
-	Short: "Set the Java version for the current directory",
-	Long:  "Creates a .jvman file in the current directory to specify the Java version",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runUse,
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change jvman settings",
 }
 
-func runUse(cmd *cobra.Command, args []string) error {
-	version := args[0]
-
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := lookupSetting(args[0])
+		if err != nil {
+			return err
+		}
 
-	reg := registry.New(cfg)
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
 
-	name := resolveInstalledName(reg, version)
-	if name == "" {
-		return fmt.Errorf("Java version %s is not installed. Run 'jvman install %s' first", version, version)
-	}
+		value := st.get(&cfg.Settings)
+		if value == "" {
+			value = st.def()
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
 
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
-	}
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := lookupSetting(args[0])
+		if err != nil {
+			return err
+		}
 
-	localFile := filepath.Join(cwd, paths.LocalVersionFile())
-	if err := os.WriteFile(localFile, []byte(name+"\n"), 0644); err != nil {
-		return fmt.Errorf("failed to create .jvman file: %w", err)
-	}
+		var settingErr error
+		cfg, err := config.Update(func(cfg *config.Config) error {
+			settingErr = st.set(&cfg.Settings, args[1])
+			return settingErr
+		})
+		if settingErr != nil {
+			return settingErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
 
-	fmt.Printf("Created .jvman file with version %s\n", name)
-	return nil
+		fmt.Printf("%s = %s\n", args[0], st.get(&cfg.Settings))
+		return nil
+	},
 }
 
-var removeCmd = &cobra.Command{
-	Use:   "remove <version>",
-	Short: "Remove an installed Java version",
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Restore the default of a setting",
 	Args:  cobra.ExactArgs(1),
-	RunE:  runRemove,
-}
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := lookupSetting(args[0])
+		if err != nil {
+			return err
+		}
 
-func runRemove(cmd *cobra.Command, args []string) error {
-	version := args[0]
+		_, err = config.Update(func(cfg *config.Config) error {
+			st.unset(&cfg.Settings)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
 
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
+		fmt.Printf("%s = %s (default)\n", args[0], st.def())
+		return nil
+	},
+}
 
-	reg := registry.New(cfg)
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all settings",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
 
-	name := resolveInstalledName(reg, version)
-	if name == "" {
-		return fmt.Errorf("Java version %s is not installed", version)
-	}
+		for _, key := range settingNames {
+			st := settings[key]
+			if value := st.get(&cfg.Settings); value != "" {
+				fmt.Printf("%s = %s\n", key, value)
+			} else {
+				fmt.Printf("%s = %s (default)\n", key, st.def())
+			}
+		}
+		return nil
+	},
+}
 
-	if err := reg.Remove(name); err != nil {
-		return fmt.Errorf("failed to remove: %w", err)
+func init() {
+	var long strings.Builder
+	long.WriteString("View and change jvman settings.\n\nSettings:")
+	for _, key := range settingNames {
+		fmt.Fprintf(&long, "\n  %-17s %s", key, settings[key].usage)
 	}
+	configCmd.Long = long.String()
 
-	fmt.Printf("Removed %s\n", name)
-	return nil
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
 }
 
 var whichCmd = &cobra.Command{
@@ -379,6 +1908,14 @@ func runWhich(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to resolve version: %w", err)
 	}
 
+	if whichHome {
+		if resolution == nil {
+			return fmt.Errorf("no Java version is currently configured")
+		}
+		fmt.Println(resolution.Path)
+		return nil
+	}
+
 	if resolution == nil {
 		fmt.Println("No Java version is currently configured")
 		return nil
@@ -387,6 +1924,21 @@ func runWhich(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Version: %s\n", resolution.Version)
 	fmt.Printf("Path: %s\n", resolution.Path)
 	fmt.Printf("Source: %s\n", resolution.Source)
+	fmt.Printf("Arch: %s\n", resolution.Arch)
+	if resolution.Channel == provider.ChannelEA {
+		fmt.Println("Channel: early access")
+	}
+	if c, ok := lifecycle.Load().Lookup(resolution.Vendor, resolution.Major); ok {
+		if summary := c.Summary(time.Now()); summary != "" {
+			fmt.Printf("Support: %s\n", summary)
+		}
+	}
+	if resolution.Pinned {
+		fmt.Println("Pinned: yes")
+	}
+	if !resolution.Runnable {
+		fmt.Printf("Warning: %s cannot run on this machine\n", resolution.Version)
+	}
 
 	javaBin := paths.JavaBinaryPath(resolution.Path)
 	fmt.Printf("Java binary: %s\n", javaBin)
@@ -421,12 +1973,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create directories: %w", err)
 	}
 
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-
-	if err := config.Save(cfg); err != nil {
+	if _, err := config.Update(func(*config.Config) error { return nil }); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
@@ -438,6 +1985,20 @@ func runInit(cmd *cobra.Command, args []string) error {
 	binDir, _ := paths.BinDir()
 	fmt.Println("jvman initialized successfully!")
 	fmt.Println()
+
+	if name, value, _ := paths.LayoutEnv(); name != "" {
+		baseDir, _ := paths.BaseDir()
+		configDir, _ := paths.ConfigDir()
+		cacheDir, _ := paths.CacheDir()
+		fmt.Printf("JDKs and shims: %s\n", baseDir)
+		fmt.Printf("Config:         %s\n", configDir)
+		fmt.Printf("Cache:          %s\n", cacheDir)
+		fmt.Println()
+		fmt.Println("Set the same location in your shell profile so that jvman finds it:")
+		fmt.Printf("  export %s=\"%s\"\n", name, value)
+		fmt.Println()
+	}
+
 	fmt.Println("Add the shim directory to your shell profile (for java, javac, etc.):")
 	fmt.Printf("  export PATH=\"%s:$PATH\"\n", binDir)
 
@@ -465,9 +2026,9 @@ func runExec(cmd *cobra.Command, args []string) error {
 
 	reg := registry.New(cfg)
 
-	name := resolveInstalledName(reg, version)
-	if name == "" {
-		return fmt.Errorf("Java version %s is not installed. Run 'jvman install %s' first", version, version)
+	name, err := resolveInstalledName(reg, version)
+	if err != nil {
+		return err
 	}
 
 	jvm, err := reg.Get(name)
@@ -554,6 +2115,55 @@ func init() {
 	cacheCmd.AddCommand(cacheClearCmd)
 }
 
+var lifecycleCmd = &cobra.Command{
+	Use:   "lifecycle",
+	Short: "Show support lifecycle dates for each vendor's Java versions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printLifecycle(lifecycle.Load())
+		return nil
+	},
+}
+
+var lifecycleRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Fetch current lifecycle data from endoflife.date",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := lifecycle.Refresh()
+		if err != nil {
+			return err
+		}
+		printLifecycle(data)
+		return nil
+	},
+}
+
+func init() {
+	lifecycleCmd.AddCommand(lifecycleRefreshCmd)
+}
+
+func printLifecycle(data lifecycle.Data) {
+	now := time.Now()
+	for i, vendorName := range defaultVendors {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s:\n", vendorName)
+		fmt.Printf("  %-6s %-11s %-11s %-11s %s\n", "Java", "GA", "EOL", "Extended", "Status")
+		for _, c := range data[vendorName] {
+			fmt.Printf("  %-6d %-11s %-11s %-11s %s\n", c.Major, orDash(c.GA), orDash(c.EOL), orDash(c.ExtendedSupport), c.Summary(now))
+		}
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 var upgradeCmd = &cobra.Command{
 	Use:   "upgrade",
 	Short: "Upgrade jvman to the latest version",
@@ -577,24 +2187,51 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func resolveInstalledName(reg *registry.Registry, version string) string {
-	if reg.IsInstalled(version) {
+// vendorPreference returns the order in which vendors are tried.
+func vendorPreference(cfg *config.Config) []string {
+	if len(cfg.Settings.Vendors) > 0 {
+		return cfg.Settings.Vendors
+	}
+	return defaultVendors
+}
+
+// selectionTarget returns what global and use should store for a requested
+// version: the alias itself when one was given, so that re-pointing the
+// alias re-targets every place it is used, and the resolved name otherwise.
+func selectionTarget(reg *registry.Registry, version, name string) string {
+	if _, ok := reg.Alias(version); ok {
 		return version
 	}
+	return name
+}
 
-	for _, versionNameFunc := range versionNameFuncs {
-		name := versionNameFunc(version)
-		if reg.IsInstalled(name) {
-			return name
-		}
+func describeTarget(target, name string) string {
+	if target == name {
+		return name
 	}
+	return fmt.Sprintf("%s (%s)", target, name)
+}
 
-	installed := reg.List()
-	for name := range installed {
-		if strings.Contains(name, version) {
-			return name
-		}
+// checkRunnable is Registry.CheckRunnable with a hint about --force.
+func checkRunnable(reg *registry.Registry, name string, force bool) error {
+	err := reg.CheckRunnable(name, force)
+	if errors.Is(err, registry.ErrNotRunnable) {
+		return fmt.Errorf("%w (use --force to select it anyway)", err)
+	}
+	return err
+}
+
+func resolveInstalledName(reg *registry.Registry, version string) (string, error) {
+	// A bare name that is neither installed, an alias nor a spec is most
+	// likely a typo or a removed alias, not a malformed version.
+	_, isAlias := reg.Alias(version)
+	if _, err := jdkversion.ParseSpec(version); err != nil && !reg.IsInstalled(version) && !isAlias {
+		return "", fmt.Errorf("%s is not installed. Run 'jvman list' to see installations and aliases", version)
 	}
 
-	return ""
+	name, err := reg.Find(version)
+	if errors.Is(err, registry.ErrNotFound) {
+		return "", fmt.Errorf("Java version %s is not installed. Run 'jvman install %s' first", version, version)
+	}
+	return name, err
 }
@@ -6,13 +6,14 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/maskedsyntax/jvman/internal/fsutil"
 	"github.com/maskedsyntax/jvman/internal/paths"
 	"github.com/maskedsyntax/jvman/internal/provider"
 )
 
 const (
 	cacheFileName = "cache.json"
-	defaultTTL    = 1 * time.Hour
+	DefaultTTL    = 1 * time.Hour
 )
 
 type VendorCache struct {
@@ -31,19 +32,16 @@ type Cache struct {
 }
 
 func New() (*Cache, error) {
-	baseDir, err := paths.BaseDir()
+	cacheDir, err := paths.CacheDir()
 	if err != nil {
 		return nil, err
 	}
 
-	filePath := filepath.Join(baseDir, cacheFileName)
+	filePath := filepath.Join(cacheDir, cacheFileName)
 
 	c := &Cache{
-		data: CacheData{
-			Vendors: make(map[string]VendorCache),
-		},
 		filePath: filePath,
-		ttl:      defaultTTL,
+		ttl:      DefaultTTL,
 	}
 
 	c.load()
@@ -51,6 +49,8 @@ func New() (*Cache, error) {
 }
 
 func (c *Cache) load() {
+	c.data = CacheData{Vendors: make(map[string]VendorCache)}
+
 	data, err := os.ReadFile(c.filePath)
 	if err != nil {
 		return
@@ -62,17 +62,34 @@ func (c *Cache) load() {
 	}
 }
 
-func (c *Cache) save() error {
+// update reloads the cache under a lock held across processes, applies fn
+// and saves the result, so that entries other jvman processes wrote in the
+// meantime are kept.
+func (c *Cache) update(fn func()) error {
 	if err := paths.EnsureDirectories(); err != nil {
 		return err
 	}
 
+	lock, err := fsutil.Acquire(c.filePath + ".lock")
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	c.load()
+	fn()
+
 	data, err := json.MarshalIndent(c.data, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(c.filePath, data, 0644)
+	return fsutil.WriteFileAtomic(c.filePath, data, 0644)
+}
+
+// SetTTL sets how long cached version lists are used before refetching.
+func (c *Cache) SetTTL(ttl time.Duration) {
+	c.ttl = ttl
 }
 
 func (c *Cache) GetVersions(vendorName string) ([]provider.Release, bool) {
@@ -89,19 +106,22 @@ func (c *Cache) GetVersions(vendorName string) ([]provider.Release, bool) {
 }
 
 func (c *Cache) SetVersions(vendorName string, versions []provider.Release) error {
-	c.data.Vendors[vendorName] = VendorCache{
-		Versions:  versions,
-		UpdatedAt: time.Now(),
-	}
-	return c.save()
+	return c.update(func() {
+		c.data.Vendors[vendorName] = VendorCache{
+			Versions:  versions,
+			UpdatedAt: time.Now(),
+		}
+	})
 }
 
 func (c *Cache) Clear() error {
-	c.data.Vendors = make(map[string]VendorCache)
-	return c.save()
+	return c.update(func() {
+		c.data.Vendors = make(map[string]VendorCache)
+	})
 }
 
 func (c *Cache) ClearVendor(vendorName string) error {
-	delete(c.data.Vendors, vendorName)
-	return c.save()
+	return c.update(func() {
+		delete(c.data.Vendors, vendorName)
+	})
 }
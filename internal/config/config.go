@@ -3,23 +3,74 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
 
+	"github.com/maskedsyntax/jvman/internal/fsutil"
 	"github.com/maskedsyntax/jvman/internal/paths"
 )
 
 type InstalledJVM struct {
-	Path   string `json:"path"`
-	Vendor string `json:"vendor"`
+	Path      string `json:"path"`
+	Vendor    string `json:"vendor"`
+	Version   string `json:"version,omitempty"`
+	Arch      string `json:"arch,omitempty"`
+	ImageType string `json:"image_type,omitempty"`
+	JavaFX    bool   `json:"javafx,omitempty"`
+	LibC      string `json:"libc,omitempty"`
+	Channel   string `json:"channel,omitempty"`
+	Pinned    bool   `json:"pinned,omitempty"`
+	// External marks JDKs that jvman registered but did not install.
+	// Removing one only unregisters it.
+	External bool `json:"external,omitempty"`
+	// System marks entries merged in from the system store. They are never
+	// written to the user's config.
+	System bool `json:"-"`
+}
+
+// Settings holds user preferences, as opposed to the state jvman records.
+type Settings struct {
+	// Vendors is the order in which vendors are tried when installing
+	// without --vendor.
+	Vendors []string `json:"vendors,omitempty"`
+	// Arch is the architecture installed when none is given.
+	Arch string `json:"arch,omitempty"`
+	// CacheTTL is how long version lists are cached, as a duration such
+	// as "1h".
+	CacheTTL string `json:"cache_ttl,omitempty"`
+	// DownloadRetries is how often a failed download request is retried.
+	DownloadRetries *int `json:"download_retries,omitempty"`
+	// StoreDir is where JDKs are installed, once moved there with
+	// `jvman relocate`.
+	StoreDir string `json:"store_dir,omitempty"`
 }
 
 type Config struct {
+	// Version is the schema version the file was written with.
+	Version        int                     `json:"version"`
 	Global         string                  `json:"global"`
 	LocalOverrides map[string]string       `json:"local_overrides"`
 	Installed      map[string]InstalledJVM `json:"installed"`
+	// LocalFiles lists the .jvman files written by `jvman use`, so that
+	// updates can repoint them.
+	LocalFiles []string `json:"local_files,omitempty"`
+	// Aliases maps user-defined names to an installation name or version
+	// spec.
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// RemovedPins lists pinned installations that were removed with
+	// --force, so that .jvman files naming them fail instead of falling back.
+	RemovedPins []string `json:"removed_pins,omitempty"`
+	Settings    Settings `json:"settings"`
 }
 
+const (
+	lockSuffix    = ".lock"
+	backupSuffix  = ".bak"
+	corruptSuffix = ".corrupt"
+)
+
 var (
 	instance *Config
 	mu       sync.RWMutex
@@ -27,9 +78,11 @@ var (
 
 func defaultConfig() *Config {
 	return &Config{
+		Version:        CurrentVersion,
 		Global:         "",
 		LocalOverrides: make(map[string]string),
 		Installed:      make(map[string]InstalledJVM),
+		Aliases:        make(map[string]string),
 	}
 }
 
@@ -42,55 +95,197 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
-	data, err := os.ReadFile(configPath)
+	cfg, stale, err := read(configPath, false)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			instance = defaultConfig()
-			return instance, nil
-		}
-		return nil, err
-	}
-
-	cfg := &Config{}
-	if err := json.Unmarshal(data, cfg); err != nil {
 		return nil, err
 	}
 
-	if cfg.LocalOverrides == nil {
-		cfg.LocalOverrides = make(map[string]string)
-	}
-	if cfg.Installed == nil {
-		cfg.Installed = make(map[string]InstalledJVM)
+	// Migrating or restoring rewrites the file, which has to happen under the
+	// lock and against whatever is on disk by then.
+	if stale {
+		cfg, err = update(configPath, false, func(*Config) error { return nil })
+		if err != nil {
+			return nil, fmt.Errorf("failed to save repaired config: %w", err)
+		}
+	} else {
+		mergeSystem(cfg)
 	}
 
-	instance = cfg
+	setInstance(cfg)
 	return instance, nil
 }
 
+// Save overwrites the config with cfg. Prefer Update for changes, which
+// keeps what other jvman processes wrote since cfg was loaded.
 func Save(cfg *Config) error {
 	mu.Lock()
 	defer mu.Unlock()
 
+	configPath, err := paths.ConfigPath()
+	if err != nil {
+		return err
+	}
+
 	if err := paths.EnsureDirectories(); err != nil {
 		return err
 	}
 
-	configPath, err := paths.ConfigPath()
+	lock, err := fsutil.Acquire(configPath + lockSuffix)
 	if err != nil {
 		return err
 	}
+	defer lock.Release()
+
+	if err := write(cfg, configPath); err != nil {
+		return err
+	}
+
+	setInstance(cfg)
+	return nil
+}
+
+// Update reloads the config under a lock held across processes, applies fn
+// and saves the result. Nothing is saved if fn returns an error.
+func Update(fn func(cfg *Config) error) (*Config, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	configPath, err := paths.ConfigPath()
+	if err != nil {
+		return nil, err
+	}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	cfg, err := update(configPath, false, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	setInstance(cfg)
+	return cfg, nil
+}
+
+// update applies fn to the config at configPath under its lock. The user's
+// config sees the system store merged in; the system store's own config is
+// updated on its own.
+func update(configPath string, system bool, fn func(cfg *Config) error) (*Config, error) {
+	if system {
+		if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+			return nil, err
+		}
+	} else if err := paths.EnsureDirectories(); err != nil {
+		return nil, err
+	}
+
+	lock, err := fsutil.Acquire(configPath + lockSuffix)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	cfg, _, err := read(configPath, true)
+	if err != nil {
+		return nil, err
+	}
+	if !system {
+		mergeSystem(cfg)
+	}
+
+	if err := fn(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := write(cfg, configPath); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// read loads the config at configPath and reports whether it needs to be
+// rewritten because it was migrated or restored from the backup. With
+// repair set, which requires the lock, the file being replaced is kept.
+func read(configPath string, repair bool) (*Config, bool, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return defaultConfig(), false, nil
+		}
+		return nil, false, err
+	}
+
+	cfg, version, err := decode(data)
+	if err != nil {
+		cfg, err := restore(configPath, data, err, repair)
+		return cfg, true, err
+	}
+
+	if version >= CurrentVersion {
+		return cfg, false, nil
+	}
+
+	if repair {
+		backupPath := fmt.Sprintf("%s.v%d.bak", configPath, version)
+		if err := fsutil.WriteFileAtomic(backupPath, data, 0644); err != nil {
+			return nil, false, fmt.Errorf("failed to back up config before migrating: %w", err)
+		}
+	}
+	return cfg, true, nil
+}
+
+// restore falls back to the copy of the last config jvman wrote when
+// config.json cannot be decoded.
+func restore(configPath string, data []byte, decodeErr error, repair bool) (*Config, error) {
+	backup, err := os.ReadFile(configPath + backupSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("config %s is corrupt and has no backup: %w", configPath, decodeErr)
+	}
+
+	cfg, _, err := decode(backup)
+	if err != nil {
+		return nil, fmt.Errorf("config %s and its backup are corrupt: %w", configPath, decodeErr)
+	}
+
+	if repair {
+		corruptPath := configPath + corruptSuffix
+		if err := os.WriteFile(corruptPath, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to keep corrupt config: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %s was corrupt and has been restored from its backup; the damaged file is kept as %s\n",
+			configPath, corruptPath)
+	}
+	return cfg, nil
+}
+
+// write stores cfg at configPath without the entries merged in from the
+// system store, refusing to overwrite a file from a newer jvman. The same
+// contents are kept as the backup that restore falls back to.
+func write(cfg *Config, configPath string) error {
+	if cfg.Version > CurrentVersion {
+		return &NewerVersionError{Path: configPath, Version: cfg.Version}
+	}
+	cfg.Version = CurrentVersion
+
+	own := *cfg
+	own.Installed = make(map[string]InstalledJVM, len(cfg.Installed))
+	for name, jvm := range cfg.Installed {
+		if !jvm.System {
+			own.Installed[name] = jvm
+		}
+	}
+
+	data, err := json.MarshalIndent(&own, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	if err := fsutil.WriteFileAtomic(configPath, data, 0644); err != nil {
 		return err
 	}
+	return fsutil.WriteFileAtomic(configPath+backupSuffix, data, 0644)
+}
 
+func setInstance(cfg *Config) {
 	instance = cfg
-	return nil
+	paths.SetJvmsDir(cfg.Settings.StoreDir)
 }
 
 func Get() *Config {
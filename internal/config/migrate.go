@@ -0,0 +1,133 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/maskedsyntax/jvman/internal/jdkversion"
+	"github.com/maskedsyntax/jvman/internal/platform"
+)
+
+// CurrentVersion is the schema version this jvman reads and writes.
+const CurrentVersion = 1
+
+// migrations[i] upgrades a config document from schema version i to i+1.
+// Migrations work on the decoded JSON rather than on Config, so that they can
+// still see fields the current structs no longer have.
+var migrations = []func(doc map[string]interface{}) error{
+	migrateV0,
+}
+
+func init() {
+	if len(migrations) != CurrentVersion {
+		panic("config: migrations do not match CurrentVersion")
+	}
+}
+
+// NewerVersionError is returned when saving a config that was written by a
+// newer jvman, whose fields this one might drop.
+type NewerVersionError struct {
+	Path    string
+	Version int
+}
+
+func (e *NewerVersionError) Error() string {
+	return fmt.Sprintf("%s was written by a newer jvman (config version %d, this jvman supports %d); upgrade jvman to make changes",
+		e.Path, e.Version, CurrentVersion)
+}
+
+// migrate brings a config document up to CurrentVersion and returns the
+// version it started at.
+func migrate(doc map[string]interface{}) (int, error) {
+	version, err := documentVersion(doc)
+	if err != nil {
+		return 0, err
+	}
+	for v := version; v < CurrentVersion; v++ {
+		if err := migrations[v](doc); err != nil {
+			return version, fmt.Errorf("failed to migrate config from version %d: %w", v, err)
+		}
+		doc["version"] = v + 1
+	}
+	return version, nil
+}
+
+// documentVersion returns the schema version of a config document; documents
+// from before versioning have none and are version 0.
+func documentVersion(doc map[string]interface{}) (int, error) {
+	raw, ok := doc["version"]
+	if !ok {
+		return 0, nil
+	}
+	v, ok := raw.(float64)
+	if !ok || v < 0 || v != math.Trunc(v) || v > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid config version %v", raw)
+	}
+	return int(v), nil
+}
+
+// migrateV0 upgrades configs written before the schema was versioned. Those
+// releases only installed native builds and did not record versions, so
+// both are filled in from the host and the installation name.
+func migrateV0(doc map[string]interface{}) error {
+	installed, _ := doc["installed"].(map[string]interface{})
+	for name, entry := range installed {
+		jvm, ok := entry.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid entry for %s", name)
+		}
+
+		if _, ok := jvm["arch"]; !ok {
+			jvm["arch"] = platform.Arch()
+		}
+
+		if _, ok := jvm["version"]; !ok {
+			vendor, _ := jvm["vendor"].(string)
+			version := strings.TrimPrefix(name, vendor+"-")
+			if _, err := jdkversion.Parse(version); err == nil {
+				jvm["version"] = version
+			}
+		}
+	}
+	return nil
+}
+
+// decode reads a config document, migrating it if it is older than
+// CurrentVersion. It returns the version the document was written with.
+func decode(data []byte) (*Config, int, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, err
+	}
+	if doc == nil {
+		doc = make(map[string]interface{})
+	}
+
+	version, err := migrate(doc)
+	if err != nil {
+		return nil, version, err
+	}
+
+	normalized, err := json.Marshal(doc)
+	if err != nil {
+		return nil, version, err
+	}
+
+	cfg := &Config{}
+	if err := json.Unmarshal(normalized, cfg); err != nil {
+		return nil, version, err
+	}
+
+	if cfg.LocalOverrides == nil {
+		cfg.LocalOverrides = make(map[string]string)
+	}
+	if cfg.Installed == nil {
+		cfg.Installed = make(map[string]InstalledJVM)
+	}
+	if cfg.Aliases == nil {
+		cfg.Aliases = make(map[string]string)
+	}
+	return cfg, version, nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/maskedsyntax/jvman/internal/platform"
+)
+
+func TestDecodeMigratesUnversioned(t *testing.T) {
+	data := []byte(`{"global": "temurin-21", "installed": {"temurin-21": {"path": "/jvms/temurin-21", "vendor": "temurin"}}}`)
+
+	cfg, version, err := decode(data)
+	if err != nil {
+		t.Fatalf("decode returned error: %v", err)
+	}
+	if version != 0 {
+		t.Errorf("version = %d, want 0", version)
+	}
+
+	jvm := cfg.Installed["temurin-21"]
+	if jvm.Version != "21" {
+		t.Errorf("Version = %q, want %q", jvm.Version, "21")
+	}
+	if jvm.Arch != platform.Arch() {
+		t.Errorf("Arch = %q, want %q", jvm.Arch, platform.Arch())
+	}
+	if cfg.Global != "temurin-21" {
+		t.Errorf("Global = %q, want %q", cfg.Global, "temurin-21")
+	}
+}
+
+func TestDecodeCurrentVersion(t *testing.T) {
+	data := []byte(`{"version": 1, "installed": {"zulu-17.0.8": {"path": "/jvms/zulu-17.0.8", "vendor": "zulu", "version": "17.0.8", "arch": "aarch64"}}}`)
+
+	cfg, version, err := decode(data)
+	if err != nil {
+		t.Fatalf("decode returned error: %v", err)
+	}
+	if version != CurrentVersion {
+		t.Errorf("version = %d, want %d", version, CurrentVersion)
+	}
+	if arch := cfg.Installed["zulu-17.0.8"].Arch; arch != "aarch64" {
+		t.Errorf("Arch = %q, want %q", arch, "aarch64")
+	}
+}
+
+func TestDecodeNewerVersion(t *testing.T) {
+	cfg, version, err := decode([]byte(`{"version": 99, "global": "temurin-21"}`))
+	if err != nil {
+		t.Fatalf("decode returned error: %v", err)
+	}
+	if version != 99 {
+		t.Errorf("version = %d, want 99", version)
+	}
+	if cfg.Global != "temurin-21" {
+		t.Errorf("Global = %q, want %q", cfg.Global, "temurin-21")
+	}
+}
+
+func TestDecodeInvalidVersion(t *testing.T) {
+	for _, doc := range []string{
+		`{"version": -1}`,
+		`{"version": 0.5}`,
+		`{"version": 1e300}`,
+		`{"version": "1"}`,
+		`{"version": null}`,
+		`{"version": [1]}`,
+	} {
+		if _, _, err := decode([]byte(doc)); err == nil {
+			t.Errorf("decode(%s) succeeded, want error", doc)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"github.com/maskedsyntax/jvman/internal/paths"
+)
+
+// LoadSystem reads the config of the system store shared by all users. A
+// store that does not exist yet has no installations.
+func LoadSystem() (*Config, error) {
+	configPath, err := paths.SystemConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	// Users can usually not write to the system store, so a file that needs
+	// migrating is only migrated in memory.
+	cfg, _, err := read(configPath, false)
+	return cfg, err
+}
+
+// UpdateSystem is Update for the system store's config.
+func UpdateSystem(fn func(cfg *Config) error) (*Config, error) {
+	configPath, err := paths.SystemConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	return update(configPath, true, fn)
+}
+
+// mergeSystem adds the system store's installations to cfg. Installations
+// of the user's own take precedence over system ones of the same name.
+func mergeSystem(cfg *Config) {
+	system, err := LoadSystem()
+	if err != nil {
+		return
+	}
+
+	for name, jvm := range system.Installed {
+		if _, exists := cfg.Installed[name]; exists {
+			continue
+		}
+		jvm.System = true
+		cfg.Installed[name] = jvm
+	}
+}
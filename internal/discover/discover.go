@@ -0,0 +1,202 @@
+// Package discover finds JDKs that were installed without jvman, such as
+// distro packages and vendor installers.
+package discover
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/maskedsyntax/jvman/internal/extractor"
+	"github.com/maskedsyntax/jvman/internal/jdkversion"
+	"github.com/maskedsyntax/jvman/internal/platform"
+	"github.com/maskedsyntax/jvman/internal/provider"
+)
+
+// JDK describes a Java home from its release file.
+type JDK struct {
+	Home      string
+	Vendor    string
+	Version   string
+	Arch      string
+	ImageType string
+	LibC      string
+	Channel   string
+}
+
+// implementors maps IMPLEMENTOR values to jvman vendor names. Other
+// implementors are named after the first word of theirs.
+var implementors = map[string]string{
+	"Eclipse Adoptium":   "temurin",
+	"Azul Systems, Inc.": "zulu",
+	"Amazon.com Inc.":    "corretto",
+	"Oracle Corporation": "oracle",
+	"Microsoft":          "microsoft",
+	"BellSoft":           "liberica",
+	"Red Hat, Inc.":      "redhat",
+}
+
+// DefaultDirs returns the directories where JDKs are commonly installed on
+// this OS. Directories that do not exist are included; Scan skips them.
+func DefaultDirs() []string {
+	var dirs []string
+	switch runtime.GOOS {
+	case "linux":
+		dirs = []string{"/usr/lib/jvm", "/usr/lib64/jvm", "/usr/java", "/usr/local/java", "/opt", "/opt/java"}
+	case "darwin":
+		dirs = []string{"/Library/Java/JavaVirtualMachines"}
+	case "windows":
+		programFiles := os.Getenv("ProgramFiles")
+		if programFiles == "" {
+			programFiles = `C:\Program Files`
+		}
+		for _, vendor := range []string{"Java", "Eclipse Adoptium", "Zulu", "Amazon Corretto", "Microsoft", "BellSoft"} {
+			dirs = append(dirs, filepath.Join(programFiles, vendor))
+		}
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		if runtime.GOOS == "darwin" {
+			dirs = append(dirs, filepath.Join(home, "Library", "Java", "JavaVirtualMachines"))
+		}
+		// SDKMAN! and IntelliJ IDEA download JDKs here.
+		dirs = append(dirs, filepath.Join(home, ".sdkman", "candidates", "java"), filepath.Join(home, ".jdks"))
+	}
+	return dirs
+}
+
+// Scan finds the Java homes that are one of dirs or directly inside one.
+// Homes are returned as absolute paths with symlinks resolved, once each, and
+// homes without a readable release file are skipped.
+func Scan(dirs []string) []JDK {
+	var found []JDK
+	seen := make(map[string]bool)
+
+	add := func(dir string) {
+		home, ok := extractor.JavaHome(dir)
+		if !ok {
+			return
+		}
+		real, err := filepath.EvalSymlinks(home)
+		if err == nil {
+			real, err = filepath.Abs(real)
+		}
+		if err != nil || seen[real] {
+			return
+		}
+		seen[real] = true
+
+		// Register the real directory; links such as default-java may be
+		// repointed by the package manager.
+		if jdk, err := Inspect(real); err == nil {
+			found = append(found, jdk)
+		}
+	}
+
+	for _, dir := range dirs {
+		add(dir)
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if info, err := os.Stat(path); err == nil && info.IsDir() {
+				add(path)
+			}
+		}
+	}
+	return found
+}
+
+// Inspect describes the Java home at home from its release file.
+func Inspect(home string) (JDK, error) {
+	props, err := readRelease(filepath.Join(home, "release"))
+	if err != nil {
+		return JDK{}, err
+	}
+
+	version := props["JAVA_RUNTIME_VERSION"]
+	// Corretto's own version has a fourth and fifth component.
+	if corretto, ok := strings.CutPrefix(props["IMPLEMENTOR_VERSION"], "Corretto-"); ok {
+		version = corretto
+	}
+	if _, err := jdkversion.Parse(version); err != nil {
+		version = props["JAVA_VERSION"]
+	}
+	v, err := jdkversion.Parse(version)
+	if err != nil {
+		return JDK{}, fmt.Errorf("%s: unrecognised Java version %q", home, version)
+	}
+
+	jdk := JDK{
+		Home:    home,
+		Vendor:  vendorName(props["IMPLEMENTOR"]),
+		Version: version,
+		Arch:    platform.NormalizeArch(props["OS_ARCH"]),
+	}
+	if jdk.Arch == "" {
+		jdk.Arch = platform.Arch()
+	}
+	if _, err := os.Stat(filepath.Join(home, "bin", javacName())); err != nil {
+		jdk.ImageType = provider.ImageJRE
+	}
+	switch props["LIBC"] {
+	case "musl":
+		jdk.LibC = platform.LibCMusl
+	case "gnu":
+		jdk.LibC = platform.LibCGlibc
+	}
+	if v.IsEarlyAccess() {
+		jdk.Channel = provider.ChannelEA
+	}
+	return jdk, nil
+}
+
+// readRelease parses the KEY="value" lines of a JDK release file.
+func readRelease(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	props := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		props[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return props, scanner.Err()
+}
+
+func vendorName(implementor string) string {
+	if name, ok := implementors[implementor]; ok {
+		return name
+	}
+	if implementor == "N/A" {
+		implementor = ""
+	}
+
+	fields := strings.FieldsFunc(strings.ToLower(implementor), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	if len(fields) == 0 {
+		return "openjdk"
+	}
+	return fields[0]
+}
+
+func javacName() string {
+	if runtime.GOOS == "windows" {
+		return "javac.exe"
+	}
+	return "javac"
+}
@@ -13,13 +13,17 @@ import (
 	"github.com/schollz/progressbar/v3"
 )
 
+// DefaultRetries is how often a failed request is retried unless configured
+// otherwise.
+const DefaultRetries = 3
+
 type Downloader struct {
 	client *retryablehttp.Client
 }
 
 func New() *Downloader {
 	client := retryablehttp.NewClient()
-	client.RetryMax = 3
+	client.RetryMax = DefaultRetries
 	client.RetryWaitMin = 1 * time.Second
 	client.RetryWaitMax = 5 * time.Second
 	client.Logger = nil
@@ -29,6 +33,11 @@ func New() *Downloader {
 	}
 }
 
+// SetRetries sets how often a failed request is retried.
+func (d *Downloader) SetRetries(n int) {
+	d.client.RetryMax = n
+}
+
 type DownloadResult struct {
 	FilePath string
 	Checksum string
@@ -9,7 +9,10 @@ import (
 )
 
 type Extractor interface {
+	// Extract unpacks an archive and returns the Java home inside it.
 	Extract(archivePath, destDir string) (string, error)
+	// Unpack only unpacks an archive, for images without a Java home.
+	Unpack(archivePath, destDir string) error
 }
 
 func New() Extractor {
@@ -38,15 +41,8 @@ func findJavaHome(extractedDir string) (string, error) {
 			continue
 		}
 
-		candidatePath := filepath.Join(extractedDir, entry.Name())
-
-		if hasJavaBinary(candidatePath) {
-			return candidatePath, nil
-		}
-
-		contentsHome := filepath.Join(candidatePath, "Contents", "Home")
-		if hasJavaBinary(contentsHome) {
-			return contentsHome, nil
+		if home, ok := JavaHome(filepath.Join(extractedDir, entry.Name())); ok {
+			return home, nil
 		}
 	}
 
@@ -57,6 +53,34 @@ func findJavaHome(extractedDir string) (string, error) {
 	return "", fmt.Errorf("could not find java binary in extracted contents")
 }
 
+// JavaHome returns the Java home in dir: dir itself, or Contents/Home for a
+// macOS bundle such as "temurin-21.jdk".
+func JavaHome(dir string) (string, bool) {
+	if hasJavaBinary(dir) {
+		return dir, true
+	}
+
+	contentsHome := filepath.Join(dir, "Contents", "Home")
+	if hasJavaBinary(contentsHome) {
+		return contentsHome, true
+	}
+	return "", false
+}
+
+// ImageRoot returns the single top-level directory of an unpacked archive,
+// or dir itself when the archive has no single root.
+func ImageRoot(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return dir, nil
+}
+
 func hasJavaBinary(dir string) bool {
 	javaBin := filepath.Join(dir, "bin", "java")
 	if runtime.GOOS == "windows" {
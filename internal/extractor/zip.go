@@ -12,14 +12,21 @@ import (
 type ZipExtractor struct{}
 
 func (e *ZipExtractor) Extract(archivePath, destDir string) (string, error) {
+	if err := e.Unpack(archivePath, destDir); err != nil {
+		return "", err
+	}
+	return findJavaHome(destDir)
+}
+
+func (e *ZipExtractor) Unpack(archivePath, destDir string) error {
 	reader, err := zip.OpenReader(archivePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to open zip archive: %w", err)
+		return fmt.Errorf("failed to open zip archive: %w", err)
 	}
 	defer reader.Close()
 
 	if err := os.MkdirAll(destDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create destination directory: %w", err)
+		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
 	for _, file := range reader.File {
@@ -32,21 +39,21 @@ func (e *ZipExtractor) Extract(archivePath, destDir string) (string, error) {
 
 		if file.FileInfo().IsDir() {
 			if err := os.MkdirAll(target, file.Mode()); err != nil {
-				return "", fmt.Errorf("failed to create directory: %w", err)
+				return fmt.Errorf("failed to create directory: %w", err)
 			}
 			continue
 		}
 
 		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
-			return "", fmt.Errorf("failed to create parent directory: %w", err)
+			return fmt.Errorf("failed to create parent directory: %w", err)
 		}
 
 		if err := extractZipFile(file, target); err != nil {
-			return "", err
+			return err
 		}
 	}
 
-	return findJavaHome(destDir)
+	return nil
 }
 
 func extractZipFile(file *zip.File, target string) error {
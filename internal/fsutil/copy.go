@@ -0,0 +1,74 @@
+package fsutil
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// CopyTree copies the directory src to dst, which must not exist yet,
+// keeping file modes and symlinks. The contents of regular files are also
+// written to progress, if it is not nil.
+func CopyTree(src, dst string, progress io.Writer) error {
+	if _, err := os.Lstat(dst); err == nil {
+		return &fs.PathError{Op: "copy", Path: dst, Err: fs.ErrExist}
+	}
+
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return copyFile(path, target, info.Mode().Perm(), progress)
+		default:
+			// Devices, sockets and the like have no place in a JDK.
+			return nil
+		}
+	})
+}
+
+func copyFile(src, dst string, perm fs.FileMode, progress io.Writer) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = out
+	if progress != nil {
+		w = io.MultiWriter(out, progress)
+	}
+
+	if _, err := io.Copy(w, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
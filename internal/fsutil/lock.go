@@ -0,0 +1,35 @@
+package fsutil
+
+import (
+	"fmt"
+	"os"
+)
+
+// Lock is an advisory lock on a file, held across processes.
+type Lock struct {
+	f *os.File
+}
+
+// Acquire blocks until it holds an exclusive lock on path, creating the file
+// if needed. The lock file itself is never written to.
+func Acquire(path string) (*Lock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return &Lock{f: f}, nil
+}
+
+// Release gives up the lock.
+func (l *Lock) Release() error {
+	if err := unlockFile(l.f); err != nil {
+		l.f.Close()
+		return err
+	}
+	return l.f.Close()
+}
@@ -0,0 +1,281 @@
+package jdkversion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/maskedsyntax/jvman/internal/platform"
+)
+
+type Kind int
+
+const (
+	// KindPrefix selects versions starting with the given components, e.g.
+	// "21" or "21.0.3+9".
+	KindPrefix Kind = iota
+	// KindRange selects versions satisfying every constraint, e.g. ">=17 <22".
+	KindRange
+	KindLatest
+	KindLTS
+)
+
+type Constraint struct {
+	Op      string
+	Version Version
+}
+
+// Spec is a user-supplied version selector as accepted by install, global,
+// use, exec and .jvman files. A vendor may be given as "temurin@21" or
+// "temurin-21", and variant qualifiers may be appended as in "21-aarch64".
+type Spec struct {
+	Raw         string
+	Vendor      string
+	Kind        Kind
+	Version     Version
+	Constraints []Constraint
+
+	// Arch is set when the spec explicitly asks for an architecture.
+	Arch string
+	// ImageType is set when the spec asks for e.g. a JRE ("21-jre").
+	ImageType string
+	// JavaFX is set when the spec asks for a JavaFX-bundled build ("21-fx").
+	JavaFX bool
+	// LibC is set when the spec asks for a C library ("21-musl").
+	LibC string
+	// Channel is set when the spec asks for a release channel ("25-ea").
+	Channel string
+}
+
+var imageTypes = map[string]bool{
+	"jdk":        true,
+	"jre":        true,
+	"testimage":  true,
+	"debugimage": true,
+}
+
+var operators = []string{">=", "<=", ">", "<", "="}
+
+func ParseSpec(s string) (Spec, error) {
+	raw := strings.TrimSpace(s)
+	if raw == "" {
+		return Spec{}, fmt.Errorf("empty version spec")
+	}
+
+	spec := Spec{Raw: raw}
+	rest := spec.parseQualifiers(raw)
+
+	if i := strings.Index(rest, "@"); i >= 0 {
+		spec.Vendor = rest[:i]
+		rest = rest[i+1:]
+		if spec.Vendor == "" {
+			return Spec{}, fmt.Errorf("invalid version spec %q: missing vendor before '@'", raw)
+		}
+	} else if isLetter(rest[0]) && !isKeyword(rest) {
+		i := strings.Index(rest, "-")
+		if i <= 0 {
+			return Spec{}, fmt.Errorf("invalid version spec %q", raw)
+		}
+		spec.Vendor = rest[:i]
+		rest = rest[i+1:]
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return Spec{}, fmt.Errorf("invalid version spec %q: missing version", raw)
+	}
+
+	switch strings.ToLower(rest) {
+	case "latest":
+		spec.Kind = KindLatest
+		return spec, nil
+	case "lts":
+		spec.Kind = KindLTS
+		return spec, nil
+	}
+
+	if strings.ContainsAny(rest, "<>= ,") {
+		constraints, err := parseConstraints(rest)
+		if err != nil {
+			return Spec{}, fmt.Errorf("invalid version spec %q: %w", raw, err)
+		}
+		spec.Kind = KindRange
+		spec.Constraints = constraints
+		return spec, nil
+	}
+
+	v, err := Parse(rest)
+	if err != nil {
+		return Spec{}, fmt.Errorf("invalid version spec %q: %w", raw, err)
+	}
+	spec.Kind = KindPrefix
+	spec.Version = v
+	return spec, nil
+}
+
+// parseQualifiers strips trailing "-<qualifier>" tokens such as "-aarch64"
+// from s, records them on the spec and returns the remainder.
+func (spec *Spec) parseQualifiers(s string) string {
+	for {
+		i := strings.LastIndex(s, "-")
+		if i <= 0 || !spec.setQualifier(strings.ToLower(s[i+1:])) {
+			return s
+		}
+		s = s[:i]
+	}
+}
+
+func (spec *Spec) setQualifier(q string) bool {
+	switch {
+	case platform.IsArch(q) && spec.Arch == "":
+		spec.Arch = platform.NormalizeArch(q)
+	case imageTypes[q] && spec.ImageType == "":
+		spec.ImageType = q
+	case q == "fx" && !spec.JavaFX:
+		spec.JavaFX = true
+	case platform.IsLibC(q) && spec.LibC == "":
+		spec.LibC = q
+	case (q == "ea" || q == "ga") && spec.Channel == "":
+		spec.Channel = q
+	default:
+		return false
+	}
+	return true
+}
+
+func parseConstraints(s string) ([]Constraint, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+
+	var constraints []Constraint
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+
+		op := ""
+		for _, candidate := range operators {
+			if strings.HasPrefix(field, candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			op = "="
+		}
+
+		operand := strings.TrimPrefix(field, op)
+		if operand == "" {
+			if i+1 >= len(fields) {
+				return nil, fmt.Errorf("operator %s without a version", op)
+			}
+			i++
+			operand = fields[i]
+		}
+
+		v, err := Parse(operand)
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, Constraint{Op: op, Version: v})
+	}
+
+	if len(constraints) == 0 {
+		return nil, fmt.Errorf("no constraints")
+	}
+	return constraints, nil
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "latest", "lts":
+		return true
+	}
+	return false
+}
+
+func (s Spec) String() string {
+	return s.Raw
+}
+
+func (s Spec) Matches(v Version) bool {
+	switch s.Kind {
+	case KindLatest:
+		return true
+	case KindLTS:
+		return IsLTSMajor(v.Major())
+	case KindRange:
+		for _, c := range s.Constraints {
+			if !c.allows(v) {
+				return false
+			}
+		}
+		return true
+	default:
+		return v.HasPrefix(s.Version)
+	}
+}
+
+// AllowsMajor reports whether some release of the given feature version
+// could satisfy the spec. It is used to pick a major before the exact
+// releases of that major are known.
+func (s Spec) AllowsMajor(major int) bool {
+	switch s.Kind {
+	case KindLatest:
+		return true
+	case KindLTS:
+		return IsLTSMajor(major)
+	case KindRange:
+		for _, c := range s.Constraints {
+			if !c.allowsMajor(major) {
+				return false
+			}
+		}
+		return true
+	default:
+		return s.Version.Major() == major
+	}
+}
+
+// Major returns the feature version pinned by a prefix spec.
+func (s Spec) Major() (int, bool) {
+	if s.Kind != KindPrefix {
+		return 0, false
+	}
+	return s.Version.Major(), true
+}
+
+func (c Constraint) allows(v Version) bool {
+	cmp := Compare(v.truncate(c.Version), c.Version)
+	switch c.Op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
+
+func (c Constraint) allowsMajor(major int) bool {
+	cm := c.Version.Major()
+	precise := len(c.Version.Parts) > 1 || c.Version.Build != 0
+	switch c.Op {
+	case ">=":
+		return major >= cm
+	case ">":
+		return major > cm || (major == cm && precise)
+	case "<=":
+		return major <= cm
+	case "<":
+		return major < cm || (major == cm && precise)
+	default:
+		return major == cm
+	}
+}
@@ -0,0 +1,182 @@
+package jdkversion
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"21", Version{Parts: []int{21}}},
+		{"21.0.4+7", Version{Parts: []int{21, 0, 4}, Build: 7}},
+		{"jdk-21.0.4+7", Version{Parts: []int{21, 0, 4}, Build: 7}},
+		{"jdk8u392-b08", Version{Parts: []int{8, 0, 392}, Build: 8}},
+		{"8u392", Version{Parts: []int{8, 0, 392}}},
+		{"1.8.0_392", Version{Parts: []int{8, 0, 392}}},
+		{"1.8.0_392-b08", Version{Parts: []int{8, 0, 392}, Build: 8}},
+		{"17.0.8.8.1", Version{Parts: []int{17, 0, 8, 8, 1}}},
+		{"zulu21.30.15-ca-jdk21.0.1", Version{Parts: []int{21, 0, 1}}},
+		{"zulu11.66.19-ca-jdk11.0.20.1+1", Version{Parts: []int{11, 0, 20, 1}, Build: 1}},
+		{"11.0.20.1+1", Version{Parts: []int{11, 0, 20, 1}, Build: 1}},
+		{"25-ea+3", Version{Parts: []int{25}, Build: 3, Pre: "ea"}},
+		{"jdk-23+35-ea-beta", Version{Parts: []int{23}, Build: 35, Pre: "ea"}},
+		{"17.0.2+8-release", Version{Parts: []int{17, 0, 2}, Build: 8}},
+		{"17.0.2+8-LTS", Version{Parts: []int{17, 0, 2}, Build: 8}},
+		{"22-internal-adhoc.user.jdk", Version{Parts: []int{22}, Pre: "internal"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "abc", "21..4", "21.x"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestIsEarlyAccess(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"25-ea+3", true},
+		{"jdk-23+35-ea-beta", true},
+		{"21.0.4+7", false},
+		{"17.0.2+8-release", false},
+		{"22-internal-adhoc.user.jdk", false},
+	}
+	for _, tt := range tests {
+		v, err := Parse(tt.in)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", tt.in, err)
+		}
+		if got := v.IsEarlyAccess(); got != tt.want {
+			t.Errorf("Parse(%q).IsEarlyAccess() = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"21", "21.0.0", 0},
+		{"21.0.4", "21.0.3", 1},
+		{"21.0.4+7", "21.0.4+9", -1},
+		{"11.0.20", "11.0.20.1", -1},
+		{"17", "21", -1},
+		{"25-ea+3", "25", -1},
+		{"25-ea+3", "25-ea+4", -1},
+		{"8u392", "1.8.0_392", 0},
+	}
+	for _, tt := range tests {
+		a, _ := Parse(tt.a)
+		b, _ := Parse(tt.b)
+		if got := Compare(a, b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(b, a); got != -tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Spec
+	}{
+		{"21", Spec{Kind: KindPrefix, Version: Version{Parts: []int{21}}}},
+		{"21.0.3+9", Spec{Kind: KindPrefix, Version: Version{Parts: []int{21, 0, 3}, Build: 9}}},
+		{"temurin@21", Spec{Vendor: "temurin", Kind: KindPrefix, Version: Version{Parts: []int{21}}}},
+		{"temurin-21", Spec{Vendor: "temurin", Kind: KindPrefix, Version: Version{Parts: []int{21}}}},
+		{"latest", Spec{Kind: KindLatest}},
+		{"zulu@lts", Spec{Vendor: "zulu", Kind: KindLTS}},
+		{"21-aarch64", Spec{Kind: KindPrefix, Version: Version{Parts: []int{21}}, Arch: "aarch64"}},
+		{"21-jre", Spec{Kind: KindPrefix, Version: Version{Parts: []int{21}}, ImageType: "jre"}},
+		{"21-fx", Spec{Kind: KindPrefix, Version: Version{Parts: []int{21}}, JavaFX: true}},
+		{"21-musl", Spec{Kind: KindPrefix, Version: Version{Parts: []int{21}}, LibC: "musl"}},
+		{"25-ea", Spec{Kind: KindPrefix, Version: Version{Parts: []int{25}}, Channel: "ea"}},
+		{"temurin@21-jre-musl-aarch64", Spec{
+			Vendor: "temurin", Kind: KindPrefix, Version: Version{Parts: []int{21}},
+			Arch: "aarch64", ImageType: "jre", LibC: "musl",
+		}},
+		{">=17 <22", Spec{Kind: KindRange, Constraints: []Constraint{
+			{Op: ">=", Version: Version{Parts: []int{17}}},
+			{Op: "<", Version: Version{Parts: []int{22}}},
+		}}},
+		{">= 17, <= 21.0.2", Spec{Kind: KindRange, Constraints: []Constraint{
+			{Op: ">=", Version: Version{Parts: []int{17}}},
+			{Op: "<=", Version: Version{Parts: []int{21, 0, 2}}},
+		}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseSpec(tt.in)
+		if err != nil {
+			t.Errorf("ParseSpec(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		tt.want.Raw = tt.in
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSpec(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "@21", "temurin@", "temurin", ">=", "21.x"} {
+		if _, err := ParseSpec(in); err == nil {
+			t.Errorf("ParseSpec(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		spec    string
+		version string
+		want    bool
+	}{
+		{"21", "21.0.4+7", true},
+		{"21", "17.0.8+7", false},
+		{"21.0", "21.0.4+7", true},
+		{"21.0.4", "21.0.4+7", true},
+		{"21.0.4+7", "21.0.4+7", true},
+		{"21.0.4+7", "21.0.4+8", false},
+		{"21.0.0", "21", true},
+		{"11.0.20.1", "11.0.20", false},
+		{"11.0.20", "11.0.20.1", true},
+		{"latest", "8u392", true},
+		{"lts", "21.0.4", true},
+		{"lts", "22.0.1", false},
+		{">=17 <22", "21.0.4", true},
+		{">=17 <22", "22", false},
+		{"<=21", "21.0.4", true},
+		{">21", "21.0.4", false},
+		{">21.0.3", "21.0.4", true},
+		{"=17", "17.0.8", true},
+	}
+	for _, tt := range tests {
+		spec, err := ParseSpec(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseSpec(%q) returned error: %v", tt.spec, err)
+		}
+		v, err := Parse(tt.version)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", tt.version, err)
+		}
+		if got := spec.Matches(v); got != tt.want {
+			t.Errorf("ParseSpec(%q).Matches(%q) = %v, want %v", tt.spec, tt.version, got, tt.want)
+		}
+	}
+}
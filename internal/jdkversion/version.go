@@ -0,0 +1,214 @@
+package jdkversion
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed JDK version. Parts holds the dot-separated numeric
+// components (21.0.4 -> [21 0 4]); Build is the "+N" build number, or 0 when
+// unknown. Pre is set for pre-release builds such as "ea".
+type Version struct {
+	Parts []int
+	Build int
+	Pre   string
+}
+
+var (
+	java8Pattern   = regexp.MustCompile(`^(\d+)u(\d+)(?:-?b(\d+))?$`)
+	versionPattern = regexp.MustCompile(`^(\d+(?:\.\d+)*)(?:-([0-9A-Za-z.]+))?(?:\+(\d+))?(?:-([0-9A-Za-z.-]+))?$`)
+)
+
+// Parse accepts the version strings used across vendors and release files:
+// "21", "21.0.4+7", "jdk-21.0.4+7", "jdk8u392-b08", "1.8.0_392",
+// "17.0.8.8.1" (Corretto) and "zulu21.30.15-ca-jdk21.0.1" (Zulu).
+func Parse(s string) (Version, error) {
+	raw := s
+	s = strings.TrimSpace(s)
+
+	if strings.HasPrefix(s, "zulu") {
+		if i := strings.LastIndex(s, "-jdk"); i >= 0 {
+			s = s[i+len("-jdk"):]
+		} else if i := strings.LastIndex(s, "-jre"); i >= 0 {
+			s = s[i+len("-jre"):]
+		}
+	}
+	s = strings.TrimPrefix(s, "jdk-")
+	s = strings.TrimPrefix(s, "jdk")
+
+	if m := java8Pattern.FindStringSubmatch(s); m != nil {
+		major, _ := strconv.Atoi(m[1])
+		update, _ := strconv.Atoi(m[2])
+		build, _ := strconv.Atoi(m[3])
+		return Version{Parts: []int{major, 0, update}, Build: build}, nil
+	}
+
+	if strings.HasPrefix(s, "1.") {
+		s = strings.Replace(strings.TrimPrefix(s, "1."), "_", ".", 1)
+		if i := strings.Index(s, "-b"); i >= 0 {
+			s = s[:i] + "+" + strings.TrimLeft(s[i+2:], "0")
+		}
+	}
+
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid version %q", raw)
+	}
+
+	var v Version
+	for _, p := range strings.Split(m[1], ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q", raw)
+		}
+		v.Parts = append(v.Parts, n)
+	}
+
+	if m[3] != "" {
+		v.Build, _ = strconv.Atoi(m[3])
+	}
+
+	v.Pre = m[2]
+	if v.Pre == "" && hasToken(m[4], "ea") {
+		v.Pre = "ea"
+	}
+
+	return v, nil
+}
+
+// hasToken reports whether token is one of the dash- or dot-separated
+// fields of s, so "ea-beta" has the token "ea" but "release" does not.
+func hasToken(s, token string) bool {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return r == '-' || r == '.'
+	})
+	for _, field := range fields {
+		if field == token {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEarlyAccess reports whether v is an early-access build. Other
+// pre-releases, such as the "internal" builds of a source tree, are not.
+func (v Version) IsEarlyAccess() bool {
+	return hasToken(v.Pre, "ea")
+}
+
+func (v Version) Major() int {
+	if len(v.Parts) == 0 {
+		return 0
+	}
+	return v.Parts[0]
+}
+
+func (v Version) part(i int) int {
+	if i < len(v.Parts) {
+		return v.Parts[i]
+	}
+	return 0
+}
+
+func (v Version) String() string {
+	parts := make([]string, len(v.Parts))
+	for i, p := range v.Parts {
+		parts[i] = strconv.Itoa(p)
+	}
+
+	s := strings.Join(parts, ".")
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	if v.Build != 0 {
+		s += "+" + strconv.Itoa(v.Build)
+	}
+	return s
+}
+
+// HasPrefix reports whether v lies within the version written as prefix,
+// so 21.0.4+7 has the prefixes 21, 21.0, 21.0.4 and 21.0.4+7.
+func (v Version) HasPrefix(prefix Version) bool {
+	if len(prefix.Parts) > len(v.Parts) && !trailingZeros(prefix.Parts[len(v.Parts):]) {
+		return false
+	}
+	for i := range prefix.Parts {
+		if v.part(i) != prefix.Parts[i] {
+			return false
+		}
+	}
+	if prefix.Build != 0 && v.Build != prefix.Build {
+		return false
+	}
+	return true
+}
+
+func trailingZeros(parts []int) bool {
+	for _, p := range parts {
+		if p != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare returns -1, 0 or 1. Missing components compare as zero and a
+// pre-release sorts before the GA build of the same version.
+func Compare(a, b Version) int {
+	n := len(a.Parts)
+	if len(b.Parts) > n {
+		n = len(b.Parts)
+	}
+	for i := 0; i < n; i++ {
+		if c := compareInt(a.part(i), b.part(i)); c != 0 {
+			return c
+		}
+	}
+
+	if a.Pre != b.Pre {
+		if a.Pre == "" {
+			return 1
+		}
+		if b.Pre == "" {
+			return -1
+		}
+		return strings.Compare(a.Pre, b.Pre)
+	}
+
+	return compareInt(a.Build, b.Build)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// truncate keeps as much precision as ref carries, so constraints like
+// "<=21" treat every 21.x release (and its pre-releases) as equal to 21.
+func (v Version) truncate(ref Version) Version {
+	t := Version{}
+	for i := range ref.Parts {
+		t.Parts = append(t.Parts, v.part(i))
+	}
+	if ref.Build != 0 {
+		t.Build = v.Build
+	}
+	return t
+}
+
+// IsLTSMajor reports whether a feature release is a long-term support
+// release under the OpenJDK cadence (8, 11, then every fourth release from 17).
+func IsLTSMajor(major int) bool {
+	if major == 8 || major == 11 {
+		return true
+	}
+	return major >= 17 && (major-17)%4 == 0
+}
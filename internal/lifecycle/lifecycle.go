@@ -0,0 +1,257 @@
+package lifecycle
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/maskedsyntax/jvman/internal/fsutil"
+	"github.com/maskedsyntax/jvman/internal/paths"
+)
+
+const (
+	dataFileName = "lifecycle.json"
+	dateLayout   = "2006-01-02"
+	apiURL       = "https://endoflife.date/api"
+
+	// WarnWindow is how long before its end of life a release is reported
+	// as ending soon.
+	WarnWindow = 90 * 24 * time.Hour
+)
+
+// bundled is the lifecycle data known at build time. It is only a starting
+// point; `jvman lifecycle refresh` replaces it with current data.
+//
+//go:embed lifecycle.json
+var bundled []byte
+
+// products maps vendors to their endoflife.date product names.
+var products = map[string]string{
+	"temurin":  "eclipse-temurin",
+	"corretto": "amazon-corretto",
+	"zulu":     "azul-zulu",
+}
+
+// Cycle is the support lifecycle of one feature release from one vendor.
+// Dates are formatted as YYYY-MM-DD and may be empty when unknown.
+type Cycle struct {
+	Major int    `json:"major"`
+	GA    string `json:"ga,omitempty"`
+	// EOL is the end of free (premier) updates.
+	EOL string `json:"eol,omitempty"`
+	// ExtendedSupport is the end of paid extended support, if offered.
+	ExtendedSupport string `json:"extended_support,omitempty"`
+}
+
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusSupported
+	StatusEndingSoon
+	StatusEOL
+)
+
+// Data holds the lifecycle of every known feature release, per vendor.
+type Data map[string][]Cycle
+
+// Load returns the refreshed lifecycle data if there is any, otherwise the
+// bundled data.
+func Load() Data {
+	if filePath, err := dataPath(); err == nil {
+		if raw, err := os.ReadFile(filePath); err == nil {
+			var data Data
+			if err := json.Unmarshal(raw, &data); err == nil && len(data) > 0 {
+				return data
+			}
+		}
+	}
+
+	var data Data
+	if err := json.Unmarshal(bundled, &data); err != nil {
+		panic(fmt.Sprintf("invalid bundled lifecycle data: %v", err))
+	}
+	return data
+}
+
+func (d Data) Lookup(vendor string, major int) (Cycle, bool) {
+	for _, c := range d[vendor] {
+		if c.Major == major {
+			return c, true
+		}
+	}
+	return Cycle{}, false
+}
+
+// Status reports where a release is in its lifecycle at the given time.
+func (c Cycle) Status(now time.Time) Status {
+	eol, ok := parseDate(c.EOL)
+	if !ok {
+		return StatusUnknown
+	}
+	switch {
+	case !now.Before(eol):
+		return StatusEOL
+	case eol.Sub(now) <= WarnWindow:
+		return StatusEndingSoon
+	default:
+		return StatusSupported
+	}
+}
+
+// Summary describes the support status of a release in a few words, e.g.
+// "supported until 2029-12-31" or "end of life since 2024-09-30".
+func (c Cycle) Summary(now time.Time) string {
+	switch c.Status(now) {
+	case StatusEOL:
+		return "end of life since " + c.EOL
+	case StatusEndingSoon:
+		return "end of life on " + c.EOL
+	case StatusSupported:
+		return "supported until " + c.EOL
+	default:
+		return ""
+	}
+}
+
+// Warning returns a message when a release is past or close to its end of
+// life, or "" when there is nothing to warn about.
+func (d Data) Warning(vendor string, major int, now time.Time) string {
+	c, ok := d.Lookup(vendor, major)
+	if !ok {
+		return ""
+	}
+
+	switch c.Status(now) {
+	case StatusEOL:
+		return fmt.Sprintf("Java %d (%s) reached end of life on %s and no longer receives updates", major, vendor, c.EOL)
+	case StatusEndingSoon:
+		return fmt.Sprintf("Java %d (%s) reaches end of life on %s", major, vendor, c.EOL)
+	default:
+		return ""
+	}
+}
+
+// Refresh fetches current lifecycle data from endoflife.date and stores it
+// in place of the bundled data.
+func Refresh() (Data, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	data := make(Data)
+	for vendor, product := range products {
+		cycles, err := fetchCycles(client, product)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch lifecycle data for %s: %w", vendor, err)
+		}
+		data[vendor] = cycles
+	}
+
+	if err := save(data); err != nil {
+		return nil, fmt.Errorf("failed to save lifecycle data: %w", err)
+	}
+	return data, nil
+}
+
+// eolCycle is the shape returned by endoflife.date. Its eol and
+// extendedSupport fields are either a date or a boolean.
+type eolCycle struct {
+	Cycle           string          `json:"cycle"`
+	ReleaseDate     string          `json:"releaseDate"`
+	EOL             json.RawMessage `json:"eol"`
+	ExtendedSupport json.RawMessage `json:"extendedSupport"`
+}
+
+func fetchCycles(client *http.Client, product string) ([]Cycle, error) {
+	resp, err := client.Get(fmt.Sprintf("%s/%s.json", apiURL, product))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var eolCycles []eolCycle
+	if err := json.NewDecoder(resp.Body).Decode(&eolCycles); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	var cycles []Cycle
+	for _, ec := range eolCycles {
+		major, err := strconv.Atoi(ec.Cycle)
+		if err != nil {
+			continue
+		}
+		cycles = append(cycles, Cycle{
+			Major:           major,
+			GA:              ec.ReleaseDate,
+			EOL:             rawDate(ec.EOL),
+			ExtendedSupport: rawDate(ec.ExtendedSupport),
+		})
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i].Major > cycles[j].Major
+	})
+	return cycles, nil
+}
+
+// rawDate returns the date in an endoflife.date field, or "" when the field
+// holds a boolean instead.
+func rawDate(raw json.RawMessage) string {
+	var date string
+	if err := json.Unmarshal(raw, &date); err != nil {
+		return ""
+	}
+	if _, ok := parseDate(date); !ok {
+		return ""
+	}
+	return date
+}
+
+func parseDate(date string) (time.Time, bool) {
+	t, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func save(data Data) error {
+	if err := paths.EnsureDirectories(); err != nil {
+		return err
+	}
+
+	filePath, err := dataPath()
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	lock, err := fsutil.Acquire(filePath + ".lock")
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	return fsutil.WriteFileAtomic(filePath, raw, 0644)
+}
+
+func dataPath() (string, error) {
+	cacheDir, err := paths.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, dataFileName), nil
+}
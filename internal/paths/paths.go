@@ -4,14 +4,26 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 )
 
 const (
 	baseDirName   = ".jvman"
+	appDirName    = "jvman"
 	jvmsDirName   = "jvms"
 	binDirName    = "bin"
 	configName    = "config.json"
 	localFileName = ".jvman"
+
+	// HomeEnv moves everything jvman stores into one directory.
+	HomeEnv = "JVMAN_HOME"
+	// XDGEnv, when true and HomeEnv is unset, splits jvman's files over the
+	// XDG base directories: JDKs and shims under XDG_DATA_HOME, the config
+	// under XDG_CONFIG_HOME and cached downloads under XDG_CACHE_HOME.
+	XDGEnv = "JVMAN_XDG"
+	// SystemEnv overrides where the system-wide store shared by all users
+	// lives.
+	SystemEnv = "JVMAN_SYSTEM_HOME"
 )
 
 func homeDir() (string, error) {
@@ -24,7 +36,16 @@ func homeDir() (string, error) {
 	return os.UserHomeDir()
 }
 
+// BaseDir holds the JDKs and shims: $JVMAN_HOME, the XDG data directory or
+// ~/.jvman.
 func BaseDir() (string, error) {
+	if dir := os.Getenv(HomeEnv); dir != "" {
+		return filepath.Abs(dir)
+	}
+	if useXDG() {
+		return xdgDir("XDG_DATA_HOME", ".local", "share")
+	}
+
 	home, err := homeDir()
 	if err != nil {
 		return "", err
@@ -32,7 +53,109 @@ func BaseDir() (string, error) {
 	return filepath.Join(home, baseDirName), nil
 }
 
+// ConfigDir holds config.json. It is BaseDir unless the XDG layout is used.
+func ConfigDir() (string, error) {
+	if useXDG() {
+		return xdgDir("XDG_CONFIG_HOME", ".config")
+	}
+	return BaseDir()
+}
+
+// CacheDir holds data jvman can fetch again, such as version lists. It is
+// BaseDir unless the XDG layout is used.
+func CacheDir() (string, error) {
+	if useXDG() {
+		return xdgDir("XDG_CACHE_HOME", ".cache")
+	}
+	return BaseDir()
+}
+
+// SystemDir is the system-wide store that an administrator fills with
+// `jvman install --system`: $JVMAN_SYSTEM_HOME, %ProgramData%\jvman on
+// Windows or /opt/jvman.
+func SystemDir() (string, error) {
+	if dir := os.Getenv(SystemEnv); dir != "" {
+		return filepath.Abs(dir)
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("ProgramData"); dir != "" {
+			return filepath.Join(dir, appDirName), nil
+		}
+		return filepath.Join(`C:\ProgramData`, appDirName), nil
+	}
+	return filepath.Join("/opt", appDirName), nil
+}
+
+func SystemJvmsDir() (string, error) {
+	dir, err := SystemDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, jvmsDirName), nil
+}
+
+func SystemConfigPath() (string, error) {
+	dir, err := SystemDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configName), nil
+}
+
+// LayoutEnv returns the environment variable and value that select the
+// current layout, so that shims can hand it on to jvman. The name is empty
+// for the default ~/.jvman.
+func LayoutEnv() (string, string, error) {
+	if os.Getenv(HomeEnv) != "" {
+		base, err := BaseDir()
+		return HomeEnv, base, err
+	}
+	if useXDG() {
+		return XDGEnv, "1", nil
+	}
+	return "", "", nil
+}
+
+func useXDG() bool {
+	if os.Getenv(HomeEnv) != "" {
+		return false
+	}
+	enabled, _ := strconv.ParseBool(os.Getenv(XDGEnv))
+	return enabled
+}
+
+// xdgDir returns the jvman directory under the XDG variable env, falling
+// back to the given path under the home directory as the spec requires.
+func xdgDir(env string, fallback ...string) (string, error) {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appDirName), nil
+	}
+
+	home, err := homeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append(append([]string{home}, fallback...), appDirName)...), nil
+}
+
+var jvmsDirOverride string
+
+// SetJvmsDir makes JvmsDir return dir, for a store moved with `jvman
+// relocate`. An empty dir restores the default.
+func SetJvmsDir(dir string) {
+	jvmsDirOverride = dir
+}
+
+// JvmsDir is where JDKs are installed.
 func JvmsDir() (string, error) {
+	if jvmsDirOverride != "" {
+		return jvmsDirOverride, nil
+	}
+	return DefaultJvmsDir()
+}
+
+// DefaultJvmsDir is where JDKs are installed unless the store was moved.
+func DefaultJvmsDir() (string, error) {
 	base, err := BaseDir()
 	if err != nil {
 		return "", err
@@ -49,11 +172,11 @@ func BinDir() (string, error) {
 }
 
 func ConfigPath() (string, error) {
-	base, err := BaseDir()
+	dir, err := ConfigDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(base, configName), nil
+	return filepath.Join(dir, configName), nil
 }
 
 func JvmPath(name string) (string, error) {
@@ -69,7 +192,7 @@ func LocalVersionFile() string {
 }
 
 func EnsureDirectories() error {
-	dirs := []func() (string, error){BaseDir, JvmsDir, BinDir}
+	dirs := []func() (string, error){BaseDir, JvmsDir, BinDir, ConfigDir, CacheDir}
 	for _, dirFn := range dirs {
 		dir, err := dirFn()
 		if err != nil {
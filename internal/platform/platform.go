@@ -0,0 +1,90 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+const (
+	LibCGlibc = "glibc"
+	LibCMusl  = "musl"
+)
+
+// Arch returns the native architecture using the names jvman records in the
+// registry (x64, aarch64, x86, ...).
+func Arch() string {
+	return NormalizeArch(runtime.GOARCH)
+}
+
+// NormalizeArch maps Go and vendor architecture names to the names jvman
+// records in the registry.
+func NormalizeArch(arch string) string {
+	switch arch {
+	case "amd64", "x86_64", "x64":
+		return "x64"
+	case "arm64", "aarch64":
+		return "aarch64"
+	case "386", "x86", "x32", "i386", "i686":
+		return "x86"
+	case "arm", "arm32":
+		return "arm"
+	default:
+		return arch
+	}
+}
+
+// IsArch reports whether s names an architecture jvman knows about.
+func IsArch(s string) bool {
+	switch NormalizeArch(s) {
+	case "x64", "aarch64", "x86", "arm", "ppc64le", "ppc64", "s390x", "riscv64":
+		return true
+	}
+	return false
+}
+
+// CanRun reports whether binaries built for arch run on this machine, either
+// natively or through the emulation the OS ships with (Rosetta on macOS,
+// x64 emulation on Windows on ARM, 32-bit x86 on x64).
+func CanRun(arch string) bool {
+	arch = NormalizeArch(arch)
+	native := Arch()
+	if arch == native {
+		return true
+	}
+
+	switch {
+	case native == "aarch64" && arch == "x64":
+		return runtime.GOOS == "darwin" || runtime.GOOS == "windows"
+	case native == "x64" && arch == "x86":
+		return runtime.GOOS == "windows" || runtime.GOOS == "linux"
+	}
+	return false
+}
+
+var (
+	libcOnce sync.Once
+	libc     string
+)
+
+// LibC returns the C library of a Linux host ("glibc" or "musl"), or an
+// empty string on other operating systems.
+func LibC() string {
+	libcOnce.Do(func() {
+		if runtime.GOOS != "linux" {
+			return
+		}
+		libc = LibCGlibc
+		if matches, _ := filepath.Glob("/lib/ld-musl-*.so.1"); len(matches) > 0 {
+			libc = LibCMusl
+		} else if _, err := os.Stat("/etc/alpine-release"); err == nil {
+			libc = LibCMusl
+		}
+	})
+	return libc
+}
+
+func IsLibC(s string) bool {
+	return s == LibCGlibc || s == LibCMusl
+}
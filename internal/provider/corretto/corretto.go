@@ -2,12 +2,17 @@ package corretto
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"time"
 
+	"github.com/maskedsyntax/jvman/internal/jdkversion"
+	"github.com/maskedsyntax/jvman/internal/platform"
 	"github.com/maskedsyntax/jvman/internal/provider"
 )
 
@@ -17,6 +22,15 @@ const (
 	vendorName   = "corretto"
 )
 
+// GitHub caps per_page at 100.
+const (
+	pageSize = 100
+	maxPages = 5
+)
+
+var errNotFound = errors.New("not found")
+
+// supportedVersions is used when the repository list cannot be fetched.
 var supportedVersions = []int{23, 22, 21, 17, 11, 8}
 
 type Corretto struct {
@@ -69,76 +83,220 @@ func normalizeArch(arch string) string {
 }
 
 type githubRelease struct {
-	TagName string `json:"tag_name"`
+	TagName     string    `json:"tag_name"`
+	PublishedAt time.Time `json:"published_at"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
 }
 
-func (c *Corretto) ListAvailableVersions() ([]provider.Release, error) {
+func (c *Corretto) ListAvailableVersions(opts *provider.Options) ([]provider.Release, error) {
+	if opts != nil && opts.Channel == provider.ChannelEA {
+		return nil, fmt.Errorf("%s does not publish early-access builds", vendorName)
+	}
+
+	versions, err := c.repoVersions()
+	if err != nil || len(versions) == 0 {
+		// GitHub rate-limits anonymous requests; fall back to the majors
+		// known at build time rather than failing outright.
+		versions = supportedVersions
+	}
+
+	// Corretto follows OpenJDK's LTS cadence and publishes no LTS marker of
+	// its own.
 	var releases []provider.Release
-	for _, v := range supportedVersions {
+	for _, v := range versions {
 		releases = append(releases, provider.Release{
 			Version: strconv.Itoa(v),
 			Vendor:  vendorName,
+			LTS:     jdkversion.IsLTSMajor(v),
 		})
 	}
 	return releases, nil
 }
 
-func (c *Corretto) GetRelease(version string, opts *provider.Options) (*provider.Release, error) {
-	os := mapOS()
-	arch := mapArch()
+var repoPattern = regexp.MustCompile(`^corretto-(\d+)$`)
+
+// repoVersions lists the majors that have a corretto-N repository.
+func (c *Corretto) repoVersions() ([]int, error) {
+	var repos []struct {
+		Name string `json:"name"`
+	}
+	if err := c.getJSON(fmt.Sprintf("https://api.github.com/orgs/corretto/repos?per_page=%d", pageSize), &repos); err != nil {
+		return nil, err
+	}
+
+	var versions []int
+	for _, repo := range repos {
+		m := repoPattern.FindStringSubmatch(repo.Name)
+		if m == nil {
+			continue
+		}
+		if v, err := strconv.Atoi(m[1]); err == nil {
+			versions = append(versions, v)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+	return versions, nil
+}
+
+// query holds the resolved request options for Corretto downloads.
+type query struct {
+	os        string
+	arch      string
+	imageType string
+	libc      string
+}
+
+func newQuery(version string, opts *provider.Options) (query, error) {
+	q := query{
+		os:        mapOS(),
+		arch:      mapArch(),
+		imageType: provider.ImageJDK,
+		libc:      platform.LibC(),
+	}
+
 	if opts != nil && opts.Arch != "" {
-		arch = normalizeArch(opts.Arch)
+		q.arch = normalizeArch(opts.Arch)
+	}
+	if opts != nil && opts.ImageType != "" {
+		q.imageType = opts.ImageType
+	}
+	if opts != nil && opts.LibC != "" {
+		q.libc = opts.LibC
+	}
+
+	switch {
+	case q.imageType == provider.ImageJDK:
+	case q.imageType == provider.ImageJRE && version == "8":
+		// Corretto only publishes separate JRE builds for Java 8.
+	default:
+		return query{}, fmt.Errorf("image type %s is not available from %s for Java %s", q.imageType, vendorName, version)
+	}
+
+	if opts != nil && opts.JavaFX {
+		return query{}, fmt.Errorf("%s does not publish JavaFX-bundled builds", vendorName)
+	}
+
+	if opts != nil && opts.Channel == provider.ChannelEA {
+		return query{}, fmt.Errorf("%s does not publish early-access builds", vendorName)
 	}
 
-	repoName := fmt.Sprintf("corretto-%s", version)
-	apiURL := fmt.Sprintf("%s/%s/releases/latest", githubAPI, repoName)
+	return q, nil
+}
 
+func (q query) release(version string, gr githubRelease) provider.Release {
+	downloadOS := q.os
+	if q.libc == platform.LibCMusl {
+		downloadOS = "alpine-linux"
+	}
+	downloadURL, fileName := buildDownloadURL(gr.TagName, downloadOS, q.arch, q.imageType)
+
+	return provider.Release{
+		Version:      version,
+		FullVersion:  gr.TagName,
+		Vendor:       vendorName,
+		DownloadURL:  downloadURL,
+		Checksum:     "",
+		ChecksumType: "",
+		FileName:     fileName,
+		OS:           q.os,
+		Arch:         q.arch,
+		ImageType:    q.imageType,
+		LibC:         q.libc,
+		Channel:      provider.ChannelGA,
+		ReleaseDate:  gr.PublishedAt,
+	}
+}
+
+func (c *Corretto) GetRelease(version string, opts *provider.Options) (*provider.Release, error) {
+	q, err := newQuery(version, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var release githubRelease
+	if err := c.getJSON(fmt.Sprintf("%s/corretto-%s/releases/latest", githubAPI, version), &release); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("version %s not found", version)
+		}
+		return nil, err
+	}
+
+	rel := q.release(version, release)
+	return &rel, nil
+}
+
+// ListReleases returns every build of a feature release, newest first.
+// Corretto does not publish archive sizes, so Size is left unset.
+func (c *Corretto) ListReleases(version string, opts *provider.Options) ([]provider.Release, error) {
+	q, err := newQuery(version, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []provider.Release
+	for page := 1; page <= maxPages; page++ {
+		var ghReleases []githubRelease
+		apiURL := fmt.Sprintf("%s/corretto-%s/releases?per_page=%d&page=%d", githubAPI, version, pageSize, page)
+		if err := c.getJSON(apiURL, &ghReleases); err != nil {
+			if errors.Is(err, errNotFound) {
+				return nil, fmt.Errorf("version %s not found", version)
+			}
+			return nil, err
+		}
+
+		for _, gr := range ghReleases {
+			if gr.Draft || gr.Prerelease {
+				continue
+			}
+			releases = append(releases, q.release(version, gr))
+		}
+
+		if len(ghReleases) < pageSize {
+			break
+		}
+	}
+
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no releases found for version %s", version)
+	}
+	return releases, nil
+}
+
+func (c *Corretto) getJSON(apiURL string, v interface{}) error {
 	req, err := http.NewRequest("GET", apiURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch release info: %w", err)
+		return fmt.Errorf("failed to fetch release info: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("version %s not found", version)
+		return errNotFound
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	var release githubRelease
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	tag := release.TagName
-	downloadURL, fileName := buildDownloadURL(tag, os, arch)
-
-	return &provider.Release{
-		Version:      version,
-		FullVersion:  tag,
-		Vendor:       vendorName,
-		DownloadURL:  downloadURL,
-		Checksum:     "",
-		ChecksumType: "",
-		FileName:     fileName,
-		OS:           os,
-		Arch:         arch,
-	}, nil
+	return nil
 }
 
-func buildDownloadURL(tag, os, arch string) (string, string) {
+func buildDownloadURL(tag, os, arch, imageType string) (string, string) {
 	var fileName string
 
 	if os == "windows" {
-		fileName = fmt.Sprintf("amazon-corretto-%s-windows-%s-jdk.zip", tag, arch)
+		fileName = fmt.Sprintf("amazon-corretto-%s-windows-%s-%s.zip", tag, arch, imageType)
+	} else if imageType == provider.ImageJRE {
+		fileName = fmt.Sprintf("amazon-corretto-%s-%s-%s-jre.tar.gz", tag, os, arch)
 	} else {
 		fileName = fmt.Sprintf("amazon-corretto-%s-%s-%s.tar.gz", tag, os, arch)
 	}
@@ -146,7 +304,3 @@ func buildDownloadURL(tag, os, arch string) (string, string) {
 	downloadURL := fmt.Sprintf("%s/%s/%s", downloadBase, tag, fileName)
 	return downloadURL, fileName
 }
-
-func VersionName(version string) string {
-	return fmt.Sprintf("%s-%s", vendorName, version)
-}
@@ -2,14 +2,15 @@ package temurin
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"runtime"
 	"sort"
 	"strconv"
-	"strings"
 	"time"
 
+	"github.com/maskedsyntax/jvman/internal/platform"
 	"github.com/maskedsyntax/jvman/internal/provider"
 )
 
@@ -18,6 +19,14 @@ const (
 	vendorName = "temurin"
 )
 
+// The assets API caps page_size at 20.
+const (
+	pageSize = 20
+	maxPages = 10
+)
+
+var errNotFound = errors.New("not found")
+
 type Temurin struct {
 	client *http.Client
 }
@@ -72,8 +81,10 @@ func normalizeArch(arch string) string {
 }
 
 type availableRelease struct {
-	Versions []int `json:"available_releases"`
-	LTS      []int `json:"available_lts_releases"`
+	Versions                 []int `json:"available_releases"`
+	LTS                      []int `json:"available_lts_releases"`
+	MostRecentFeatureRelease int   `json:"most_recent_feature_release"`
+	TipVersion               int   `json:"tip_version"`
 }
 
 type packageInfo struct {
@@ -91,6 +102,14 @@ type binaryInfo struct {
 	Package      packageInfo `json:"package"`
 }
 
+// featureRelease is the shape returned by /assets/feature_releases, which
+// unlike /assets/latest lists every build of a release.
+type featureRelease struct {
+	Binaries    []binaryInfo `json:"binaries"`
+	ReleaseName string       `json:"release_name"`
+	Timestamp   time.Time    `json:"timestamp"`
+}
+
 type releaseInfo struct {
 	Binary      binaryInfo `json:"binary"`
 	ReleaseName string     `json:"release_name"`
@@ -102,7 +121,7 @@ type releaseInfo struct {
 	} `json:"version"`
 }
 
-func (t *Temurin) ListAvailableVersions() ([]provider.Release, error) {
+func (t *Temurin) ListAvailableVersions(opts *provider.Options) ([]provider.Release, error) {
 	url := fmt.Sprintf("%s/info/available_releases", baseURL)
 	resp, err := t.client.Get(url)
 	if err != nil {
@@ -119,69 +138,215 @@ func (t *Temurin) ListAvailableVersions() ([]provider.Release, error) {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	sort.Sort(sort.Reverse(sort.IntSlice(available.Versions)))
+	versions := available.Versions
+	if opts != nil && opts.Channel == provider.ChannelEA {
+		versions = nil
+		for v := available.MostRecentFeatureRelease + 1; v <= available.TipVersion; v++ {
+			versions = append(versions, v)
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+	lts := make(map[int]bool)
+	for _, v := range available.LTS {
+		lts[v] = true
+	}
 
 	var releases []provider.Release
-	for _, v := range available.Versions {
+	for _, v := range versions {
 		releases = append(releases, provider.Release{
 			Version: strconv.Itoa(v),
 			Vendor:  vendorName,
+			LTS:     lts[v],
 		})
 	}
 
 	return releases, nil
 }
 
-func (t *Temurin) GetRelease(version string, opts *provider.Options) (*provider.Release, error) {
-	os := mapOS()
-	arch := mapArch()
+// query holds the resolved request options for the Adoptium API.
+type query struct {
+	os        string
+	arch      string
+	imageType string
+	libc      string
+	channel   string
+}
+
+func newQuery(opts *provider.Options) (query, error) {
+	q := query{
+		os:        mapOS(),
+		arch:      mapArch(),
+		imageType: provider.ImageJDK,
+		libc:      platform.LibC(),
+		channel:   provider.ChannelGA,
+	}
+
 	if opts != nil && opts.Arch != "" {
-		arch = normalizeArch(opts.Arch)
+		q.arch = normalizeArch(opts.Arch)
+	}
+	if opts != nil && opts.ImageType != "" {
+		q.imageType = opts.ImageType
+	}
+	if opts != nil && opts.LibC != "" {
+		q.libc = opts.LibC
+	}
+	if opts != nil && opts.Channel != "" {
+		q.channel = opts.Channel
 	}
 
-	url := fmt.Sprintf(
-		"%s/assets/latest/%s/hotspot?architecture=%s&image_type=jdk&os=%s&vendor=eclipse",
-		baseURL, version, arch, os,
-	)
+	switch q.imageType {
+	case provider.ImageJDK, provider.ImageJRE, provider.ImageTest, provider.ImageDebug:
+	default:
+		return query{}, fmt.Errorf("image type %s is not available from %s", q.imageType, vendorName)
+	}
 
-	resp, err := t.client.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch release info: %w", err)
+	if opts != nil && opts.JavaFX {
+		return query{}, fmt.Errorf("%s does not publish JavaFX-bundled builds", vendorName)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("version %s not found for %s/%s", version, os, arch)
+	if q.libc == platform.LibCMusl {
+		q.os = "alpine-linux"
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	return q, nil
+}
+
+func (t *Temurin) GetRelease(version string, opts *provider.Options) (*provider.Release, error) {
+	q, err := newQuery(opts)
+	if err != nil {
+		return nil, err
 	}
 
 	var releases []releaseInfo
-	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if q.channel == provider.ChannelEA {
+		var featureReleases []featureRelease
+		featureReleases, err = t.featureReleases(version, q, 1, 0)
+		for _, fr := range featureReleases {
+			for _, binary := range fr.Binaries {
+				releases = append(releases, releaseInfo{Binary: binary, ReleaseName: fr.ReleaseName})
+			}
+		}
+	} else {
+		url := fmt.Sprintf(
+			"%s/assets/latest/%s/hotspot?architecture=%s&image_type=%s&os=%s&vendor=eclipse",
+			baseURL, version, q.arch, q.imageType, q.os,
+		)
+		err = t.getJSON(url, &releases)
 	}
 
-	if len(releases) == 0 {
-		return nil, fmt.Errorf("no releases found for version %s", version)
+	if errors.Is(err, errNotFound) {
+		return nil, fmt.Errorf("version %s not found for %s/%s", version, q.os, q.arch)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var release *releaseInfo
+	for i := range releases {
+		if releases[i].Binary.ImageType == q.imageType {
+			release = &releases[i]
+			break
+		}
+	}
+
+	if release == nil {
+		return nil, fmt.Errorf("no %s %s releases found for version %s on %s/%s", q.channel, q.imageType, version, q.os, q.arch)
+	}
+
+	rel := q.release(version, release.ReleaseName, release.Binary)
+	return &rel, nil
+}
+
+// ListReleases returns every build of a feature release, newest first.
+func (t *Temurin) ListReleases(version string, opts *provider.Options) ([]provider.Release, error) {
+	q, err := newQuery(opts)
+	if err != nil {
+		return nil, err
 	}
 
-	release := releases[0]
+	var releases []provider.Release
+	for page := 0; page < maxPages; page++ {
+		featureReleases, err := t.featureReleases(version, q, pageSize, page)
+		if errors.Is(err, errNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fr := range featureReleases {
+			for _, binary := range fr.Binaries {
+				if binary.ImageType != q.imageType {
+					continue
+				}
+				rel := q.release(version, fr.ReleaseName, binary)
+				rel.ReleaseDate = fr.Timestamp
+				releases = append(releases, rel)
+				break
+			}
+		}
 
-	return &provider.Release{
+		if len(featureReleases) < pageSize {
+			break
+		}
+	}
+
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no %s releases of version %s found for %s/%s", q.channel, version, q.os, q.arch)
+	}
+	return releases, nil
+}
+
+func (q query) release(version, releaseName string, binary binaryInfo) provider.Release {
+	return provider.Release{
 		Version:      version,
-		FullVersion:  release.ReleaseName,
+		FullVersion:  releaseName,
 		Vendor:       vendorName,
-		DownloadURL:  release.Binary.Package.DownloadURL,
-		Checksum:     release.Binary.Package.Checksum,
+		DownloadURL:  binary.Package.DownloadURL,
+		Checksum:     binary.Package.Checksum,
 		ChecksumType: "sha256",
-		FileName:     release.Binary.Package.Name,
-		OS:           release.Binary.OS,
-		Arch:         release.Binary.Architecture,
-	}, nil
+		FileName:     binary.Package.Name,
+		OS:           binary.OS,
+		Arch:         binary.Architecture,
+		ImageType:    binary.ImageType,
+		LibC:         q.libc,
+		Channel:      q.channel,
+		Size:         binary.Package.Size,
+	}
 }
 
-func VersionName(version string) string {
-	return fmt.Sprintf("%s-%s", vendorName, strings.TrimPrefix(version, "jdk-"))
+func (t *Temurin) featureReleases(version string, q query, size, page int) ([]featureRelease, error) {
+	url := fmt.Sprintf(
+		"%s/assets/feature_releases/%s/%s?architecture=%s&image_type=%s&os=%s&jvm_impl=hotspot&vendor=eclipse&sort_order=DESC&page_size=%d&page=%d",
+		baseURL, version, q.channel, q.arch, q.imageType, q.os, size, page,
+	)
+
+	var releases []featureRelease
+	if err := t.getJSON(url, &releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+func (t *Temurin) getJSON(url string, v interface{}) error {
+	resp, err := t.client.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch release info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
 }
@@ -1,5 +1,19 @@
 package provider
 
+import "time"
+
+const (
+	ImageJDK   = "jdk"
+	ImageJRE   = "jre"
+	ImageTest  = "testimage"
+	ImageDebug = "debugimage"
+)
+
+const (
+	ChannelGA = "ga"
+	ChannelEA = "ea"
+)
+
 type Release struct {
 	Version      string
 	FullVersion  string
@@ -10,14 +24,33 @@ type Release struct {
 	FileName     string
 	OS           string
 	Arch         string
+	ImageType    string
+	JavaFX       bool
+	LibC         string
+	Channel      string
+	ReleaseDate  time.Time
+	Size         int64
+	LTS          bool
 }
 
 type Options struct {
-	Arch string
+	Arch      string
+	ImageType string
+	JavaFX    bool
+	LibC      string
+	Channel   string
+}
+
+// IsRuntimeImage reports whether an image type contains a runnable Java
+// home (as opposed to e.g. test or debug-symbol images).
+func IsRuntimeImage(imageType string) bool {
+	return imageType == "" || imageType == ImageJDK || imageType == ImageJRE
 }
 
 type Vendor interface {
 	Name() string
-	ListAvailableVersions() ([]Release, error)
+	ListAvailableVersions(opts *Options) ([]Release, error)
 	GetRelease(version string, opts *Options) (*Release, error)
+	// ListReleases returns every build of a feature release, newest first.
+	ListReleases(version string, opts *Options) ([]Release, error)
 }
@@ -8,8 +8,10 @@ import (
 	"runtime"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/maskedsyntax/jvman/internal/platform"
 	"github.com/maskedsyntax/jvman/internal/provider"
 )
 
@@ -18,6 +20,11 @@ const (
 	vendorName = "zulu"
 )
 
+const (
+	pageSize = 100
+	maxPages = 10
+)
+
 type Zulu struct {
 	client *http.Client
 }
@@ -79,46 +86,53 @@ func archiveType() string {
 }
 
 type zuluPackage struct {
-	DownloadURL    string `json:"download_url"`
-	Name           string `json:"name"`
-	Sha256Hash     string `json:"sha256_hash"`
-	JavaVersion    []int  `json:"java_version"`
-	ZuluVersion    []int  `json:"zulu_version"`
-	LatestInChain  bool   `json:"latest"`
+	DownloadURL   string `json:"download_url"`
+	Name          string `json:"name"`
+	Sha256Hash    string `json:"sha256_hash"`
+	JavaVersion   []int  `json:"java_version"`
+	BuildNumber   int    `json:"openjdk_build_number"`
+	ZuluVersion   []int  `json:"zulu_version"`
+	LatestInChain bool   `json:"latest"`
+	Size          int64  `json:"size"`
+	SupportTerm   string `json:"support_term"`
 }
 
-func (z *Zulu) ListAvailableVersions() ([]provider.Release, error) {
+func (z *Zulu) ListAvailableVersions(opts *provider.Options) ([]provider.Release, error) {
+	arch := mapArch()
+	if opts != nil && opts.Arch != "" {
+		arch = normalizeArch(opts.Arch)
+	}
+
+	channel := provider.ChannelGA
+	if opts != nil && opts.Channel != "" {
+		channel = opts.Channel
+	}
+
 	params := url.Values{}
 	params.Set("os", mapOS())
-	params.Set("arch", mapArch())
+	params.Set("arch", arch)
 	params.Set("archive_type", archiveType())
 	params.Set("java_package_type", "jdk")
 	params.Set("javafx_bundled", "false")
-	params.Set("release_status", "ga")
+	params.Set("release_status", channel)
 	params.Set("availability_types", "CA")
+	params.Set("latest", "true")
+	params.Set("include_fields", "support_term")
 	params.Set("page_size", "100")
 
-	reqURL := baseURL + "?" + params.Encode()
-
-	resp, err := z.client.Get(reqURL)
+	packages, err := z.packages(params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch available releases: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	var packages []zuluPackage
-	if err := json.NewDecoder(resp.Body).Decode(&packages); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
 
 	versionSet := make(map[int]bool)
+	lts := make(map[int]bool)
 	for _, pkg := range packages {
 		if len(pkg.JavaVersion) > 0 {
 			versionSet[pkg.JavaVersion[0]] = true
+			if pkg.SupportTerm == "lts" {
+				lts[pkg.JavaVersion[0]] = true
+			}
 		}
 	}
 
@@ -133,36 +147,170 @@ func (z *Zulu) ListAvailableVersions() ([]provider.Release, error) {
 		releases = append(releases, provider.Release{
 			Version: strconv.Itoa(v),
 			Vendor:  vendorName,
+			LTS:     lts[v],
 		})
 	}
 
 	return releases, nil
 }
 
-func (z *Zulu) GetRelease(version string, opts *provider.Options) (*provider.Release, error) {
-	arch := mapArch()
+// query holds the resolved request options for the Azul metadata API.
+type query struct {
+	arch      string
+	imageType string
+	javaFX    bool
+	libc      string
+	channel   string
+}
+
+func newQuery(opts *provider.Options) (query, error) {
+	q := query{
+		arch:      mapArch(),
+		imageType: provider.ImageJDK,
+		javaFX:    opts != nil && opts.JavaFX,
+		libc:      platform.LibC(),
+		channel:   provider.ChannelGA,
+	}
+
 	if opts != nil && opts.Arch != "" {
-		arch = normalizeArch(opts.Arch)
+		q.arch = normalizeArch(opts.Arch)
+	}
+	if opts != nil && opts.ImageType != "" {
+		q.imageType = opts.ImageType
+	}
+	if opts != nil && opts.LibC != "" {
+		q.libc = opts.LibC
+	}
+	if opts != nil && opts.Channel != "" {
+		q.channel = opts.Channel
 	}
 
+	if q.imageType != provider.ImageJDK && q.imageType != provider.ImageJRE {
+		return query{}, fmt.Errorf("image type %s is not available from %s", q.imageType, vendorName)
+	}
+
+	return q, nil
+}
+
+func (q query) params(version string) url.Values {
 	params := url.Values{}
 	params.Set("os", mapOS())
-	params.Set("arch", arch)
+	params.Set("arch", q.arch)
 	params.Set("archive_type", archiveType())
-	params.Set("java_package_type", "jdk")
-	params.Set("javafx_bundled", "false")
-	params.Set("release_status", "ga")
+	params.Set("java_package_type", q.imageType)
+	params.Set("javafx_bundled", strconv.FormatBool(q.javaFX))
+	if q.libc != "" {
+		params.Set("lib_c_type", q.libc)
+	}
+	params.Set("release_status", q.channel)
 	params.Set("availability_types", "CA")
 	params.Set("java_version", version)
+	params.Set("include_fields", "openjdk_build_number")
+	return params
+}
+
+func (q query) release(version string, pkg zuluPackage) provider.Release {
+	packageName := q.imageType
+	if q.javaFX {
+		packageName = "fx-" + q.imageType
+	}
+
+	availability := "ca"
+	if q.channel == provider.ChannelEA {
+		availability = "ea"
+	}
+
+	fullVersion := fmt.Sprintf("zulu%d.%d.%d-%s-%s%s",
+		safeIndex(pkg.ZuluVersion, 0),
+		safeIndex(pkg.ZuluVersion, 1),
+		safeIndex(pkg.ZuluVersion, 2),
+		availability,
+		packageName,
+		javaVersion(pkg),
+	)
+
+	return provider.Release{
+		Version:      version,
+		FullVersion:  fullVersion,
+		Vendor:       vendorName,
+		DownloadURL:  pkg.DownloadURL,
+		Checksum:     pkg.Sha256Hash,
+		ChecksumType: "sha256",
+		FileName:     pkg.Name,
+		OS:           mapOS(),
+		Arch:         q.arch,
+		ImageType:    q.imageType,
+		JavaFX:       q.javaFX,
+		LibC:         q.libc,
+		Channel:      q.channel,
+		Size:         pkg.Size,
+	}
+}
+
+func (z *Zulu) GetRelease(version string, opts *provider.Options) (*provider.Release, error) {
+	q, err := newQuery(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	params := q.params(version)
 	params.Set("latest", "true")
 	params.Set("page_size", "1")
 
-	reqURL := baseURL + "?" + params.Encode()
-
-	resp, err := z.client.Get(reqURL)
+	packages, err := z.packages(params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch release info: %w", err)
 	}
+
+	if len(packages) == 0 {
+		return nil, fmt.Errorf("no releases found for version %s", version)
+	}
+
+	rel := q.release(version, packages[0])
+	return &rel, nil
+}
+
+// ListReleases returns every build of a feature release, newest first. The
+// metadata API does not report release dates, so ReleaseDate is left unset.
+func (z *Zulu) ListReleases(version string, opts *provider.Options) ([]provider.Release, error) {
+	q, err := newQuery(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	params := q.params(version)
+	params.Set("include_fields", "openjdk_build_number,sha256_hash,size")
+	params.Set("page_size", strconv.Itoa(pageSize))
+
+	var releases []provider.Release
+	for page := 1; page <= maxPages; page++ {
+		params.Set("page", strconv.Itoa(page))
+
+		packages, err := z.packages(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch releases: %w", err)
+		}
+
+		for _, pkg := range packages {
+			releases = append(releases, q.release(version, pkg))
+		}
+
+		if len(packages) < pageSize {
+			break
+		}
+	}
+
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no releases found for version %s", version)
+	}
+	return releases, nil
+}
+
+func (z *Zulu) packages(params url.Values) ([]zuluPackage, error) {
+	resp, err := z.client.Get(baseURL + "?" + params.Encode())
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
@@ -173,33 +321,25 @@ func (z *Zulu) GetRelease(version string, opts *provider.Options) (*provider.Rel
 	if err := json.NewDecoder(resp.Body).Decode(&packages); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	return packages, nil
+}
 
-	if len(packages) == 0 {
-		return nil, fmt.Errorf("no releases found for version %s", version)
+// javaVersion formats every java_version component and the OpenJDK build
+// number, e.g. "11.0.20.1+1", so that patch releases get distinct names.
+func javaVersion(pkg zuluPackage) string {
+	parts := make([]string, len(pkg.JavaVersion))
+	for i, p := range pkg.JavaVersion {
+		parts[i] = strconv.Itoa(p)
 	}
 
-	pkg := packages[0]
-
-	fullVersion := fmt.Sprintf("zulu%d.%d.%d-ca-jdk%d.%d.%d",
-		safeIndex(pkg.ZuluVersion, 0),
-		safeIndex(pkg.ZuluVersion, 1),
-		safeIndex(pkg.ZuluVersion, 2),
-		safeIndex(pkg.JavaVersion, 0),
-		safeIndex(pkg.JavaVersion, 1),
-		safeIndex(pkg.JavaVersion, 2),
-	)
-
-	return &provider.Release{
-		Version:      version,
-		FullVersion:  fullVersion,
-		Vendor:       vendorName,
-		DownloadURL:  pkg.DownloadURL,
-		Checksum:     pkg.Sha256Hash,
-		ChecksumType: "sha256",
-		FileName:     pkg.Name,
-		OS:           mapOS(),
-		Arch:         arch,
-	}, nil
+	version := strings.Join(parts, ".")
+	if version == "" {
+		version = "0"
+	}
+	if pkg.BuildNumber != 0 {
+		version += "+" + strconv.Itoa(pkg.BuildNumber)
+	}
+	return version
 }
 
 func safeIndex(slice []int, index int) int {
@@ -208,7 +348,3 @@ func safeIndex(slice []int, index int) int {
 	}
 	return 0
 }
-
-func VersionName(version string) string {
-	return fmt.Sprintf("%s-%s", vendorName, version)
-}
@@ -0,0 +1,65 @@
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/maskedsyntax/jvman/internal/jdkversion"
+)
+
+var aliasPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9._-]*$`)
+
+// Alias returns the target of a user-defined alias.
+func (r *Registry) Alias(name string) (string, bool) {
+	target, ok := r.cfg.Aliases[name]
+	return target, ok
+}
+
+// AliasNames returns the defined aliases in a stable order.
+func (r *Registry) AliasNames() []string {
+	names := make([]string, 0, len(r.cfg.Aliases))
+	for name := range r.cfg.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetAlias points an alias at an installation name or version spec. The
+// alias must not be mistakable for either, and the target must currently
+// resolve to an installation.
+func (r *Registry) SetAlias(alias, target string) error {
+	if !aliasPattern.MatchString(alias) {
+		return fmt.Errorf("invalid alias %q: use letters, digits, '.', '_' and '-', starting with a letter", alias)
+	}
+	if _, err := jdkversion.ParseSpec(alias); err == nil {
+		return fmt.Errorf("invalid alias %q: it is a version spec", alias)
+	}
+
+	return r.update(func(reg *Registry) error {
+		if reg.IsInstalled(alias) {
+			return fmt.Errorf("invalid alias %q: it is the name of an installation", alias)
+		}
+		if _, ok := reg.cfg.Aliases[target]; ok {
+			return fmt.Errorf("alias target %s is itself an alias", target)
+		}
+		if _, err := reg.Find(target); err != nil {
+			return err
+		}
+
+		reg.cfg.Aliases[alias] = target
+		return nil
+	})
+}
+
+func (r *Registry) RemoveAlias(alias string) error {
+	return r.update(func(reg *Registry) error {
+		if _, ok := reg.cfg.Aliases[alias]; !ok {
+			return fmt.Errorf("alias %s does not exist", alias)
+		}
+
+		delete(reg.cfg.Aliases, alias)
+		return nil
+	})
+}
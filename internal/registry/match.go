@@ -0,0 +1,301 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/maskedsyntax/jvman/internal/config"
+	"github.com/maskedsyntax/jvman/internal/jdkversion"
+	"github.com/maskedsyntax/jvman/internal/platform"
+	"github.com/maskedsyntax/jvman/internal/provider"
+)
+
+var ErrNotFound = errors.New("no matching installation")
+
+// AmbiguousError is returned when a spec matches installations that are not
+// interchangeable (e.g. different vendors) and no single one can be chosen.
+type AmbiguousError struct {
+	Spec       string
+	Candidates []string
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("%s is ambiguous, it matches: %s (use a full name or vendor@version)",
+		e.Spec, strings.Join(e.Candidates, ", "))
+}
+
+type candidate struct {
+	name    string
+	jvm     config.InstalledJVM
+	version jdkversion.Version
+}
+
+// Find resolves a name, alias or version spec to the name of an installed
+// JVM. Candidates are ranked as follows:
+//
+//  1. an installation with exactly that name, or the target of an alias;
+//  2. installations of the vendor named by the spec ("temurin@21",
+//     "temurin-21"), or of any vendor when none is named;
+//  3. the highest version among those matching the spec.
+//
+// When candidates of more than one flavor (vendor, ...) remain, an
+// *AmbiguousError listing them is returned instead of guessing.
+func (r *Registry) Find(spec string) (string, error) {
+	if r.IsInstalled(spec) {
+		return spec, nil
+	}
+
+	if target, ok := r.Alias(spec); ok {
+		name, err := r.Find(target)
+		if err != nil {
+			return "", fmt.Errorf("alias %s: %w", spec, err)
+		}
+		return name, nil
+	}
+
+	s, err := jdkversion.ParseSpec(spec)
+	if err != nil {
+		return "", err
+	}
+
+	var candidates []candidate
+	for _, name := range r.Names() {
+		jvm := r.cfg.Installed[name]
+		if s.Vendor != "" && jvm.Vendor != s.Vendor {
+			continue
+		}
+		if !matchesVariant(s, jvm) {
+			continue
+		}
+
+		v, ok := InstalledVersion(name, jvm)
+		if !ok || !s.Matches(v) {
+			continue
+		}
+
+		candidates = append(candidates, candidate{name: name, jvm: jvm, version: v})
+	}
+
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%w for %s", ErrNotFound, spec)
+	}
+
+	// Unless the spec asks otherwise, prefer native builds over ones that
+	// merely run under emulation, plain builds over JavaFX ones and GA
+	// builds over early-access ones.
+	if s.Arch == "" {
+		candidates = prefer(candidates, func(jvm config.InstalledJVM) bool {
+			return Arch(jvm) == platform.Arch()
+		})
+	}
+	if !s.JavaFX {
+		candidates = prefer(candidates, func(jvm config.InstalledJVM) bool {
+			return !jvm.JavaFX
+		})
+	}
+	if s.Channel == "" {
+		candidates = prefer(candidates, func(jvm config.InstalledJVM) bool {
+			return Channel(jvm) == provider.ChannelGA
+		})
+	}
+
+	flavors := make(map[string]bool)
+	for _, c := range candidates {
+		flavors[flavor(c.jvm)] = true
+	}
+
+	if len(flavors) > 1 {
+		names := make([]string, len(candidates))
+		for i, c := range candidates {
+			names[i] = c.name
+		}
+		return "", &AmbiguousError{Spec: spec, Candidates: names}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return jdkversion.Compare(candidates[i].version, candidates[j].version) > 0
+	})
+	return candidates[0].name, nil
+}
+
+// prefer narrows candidates to those satisfying pred, if there are any.
+func prefer(candidates []candidate, pred func(config.InstalledJVM) bool) []candidate {
+	var preferred []candidate
+	for _, c := range candidates {
+		if pred(c.jvm) {
+			preferred = append(preferred, c)
+		}
+	}
+	if len(preferred) == 0 {
+		return candidates
+	}
+	return preferred
+}
+
+// matchesVariant checks the qualifiers of a spec against an installation.
+// Without an explicit arch or libc, only builds this machine can run are
+// eligible; without an explicit image type, only full JDKs are.
+func matchesVariant(s jdkversion.Spec, jvm config.InstalledJVM) bool {
+	if s.Arch != "" {
+		if Arch(jvm) != s.Arch {
+			return false
+		}
+	} else if !platform.CanRun(Arch(jvm)) {
+		return false
+	}
+
+	if s.LibC != "" {
+		if LibC(jvm) != s.LibC {
+			return false
+		}
+	} else if LibC(jvm) != platform.LibC() {
+		return false
+	}
+
+	if s.JavaFX && !jvm.JavaFX {
+		return false
+	}
+
+	if s.Channel != "" && Channel(jvm) != s.Channel {
+		return false
+	}
+
+	imageType := s.ImageType
+	if imageType == "" {
+		imageType = provider.ImageJDK
+	}
+	return ImageType(jvm) == imageType
+}
+
+// flavor identifies installations that are interchangeable for matching
+// purposes; a spec may only pick among candidates of a single flavor.
+func flavor(jvm config.InstalledJVM) string {
+	return jvm.Vendor + "/" + Arch(jvm)
+}
+
+// Arch returns the architecture of an installation. Entries recorded before
+// architectures were stored are assumed to be native.
+func Arch(jvm config.InstalledJVM) string {
+	if jvm.Arch == "" {
+		return platform.Arch()
+	}
+	return jvm.Arch
+}
+
+// LibC returns the C library an installation was built for. Entries
+// recorded before libc was stored are assumed to match the host.
+func LibC(jvm config.InstalledJVM) string {
+	if jvm.LibC == "" {
+		return platform.LibC()
+	}
+	return jvm.LibC
+}
+
+// Runnable reports whether this machine can run an installation.
+func Runnable(jvm config.InstalledJVM) bool {
+	return platform.CanRun(Arch(jvm)) && LibC(jvm) == platform.LibC()
+}
+
+// CheckRunnable refuses an installation without a Java runtime, such as a
+// test image, and unless force is set one this machine cannot run.
+func (r *Registry) CheckRunnable(name string, force bool) error {
+	jvm, err := r.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if !provider.IsRuntimeImage(jvm.ImageType) {
+		return fmt.Errorf("%s is a %s and has no Java runtime to select", name, jvm.ImageType)
+	}
+
+	if force || Runnable(*jvm) {
+		return nil
+	}
+	return fmt.Errorf("%s (%s) %w", name, Describe(*jvm), ErrNotRunnable)
+}
+
+func Channel(jvm config.InstalledJVM) string {
+	if jvm.Channel == "" {
+		return provider.ChannelGA
+	}
+	return jvm.Channel
+}
+
+func ImageType(jvm config.InstalledJVM) string {
+	if jvm.ImageType == "" {
+		return provider.ImageJDK
+	}
+	return jvm.ImageType
+}
+
+// InstallBase returns the name of a vendor's release before variant
+// qualifiers, keyed by its full version so that several patches of one major
+// can coexist. Early-access builds are marked by InstallName instead.
+func InstallBase(vendor, fullVersion string) string {
+	if v, err := jdkversion.Parse(fullVersion); err == nil {
+		v.Pre = ""
+		return fmt.Sprintf("%s-%s", vendor, v)
+	}
+	return fmt.Sprintf("%s-%s", vendor, strings.TrimPrefix(fullVersion, "jdk-"))
+}
+
+// InstallName appends the variant qualifiers of jvm to base, so that e.g. a
+// non-native build is installed as "temurin-21.0.4+7-aarch64", a JRE as
+// "temurin-21.0.4+7-jre" and a JavaFX build as "zulu-21.0.4-fx" next to the
+// plain native JDK.
+func InstallName(base string, jvm config.InstalledJVM) string {
+	name := base
+	if ImageType(jvm) != provider.ImageJDK {
+		name += "-" + ImageType(jvm)
+	}
+	if jvm.JavaFX {
+		name += "-fx"
+	}
+	if Channel(jvm) != provider.ChannelGA {
+		name += "-" + Channel(jvm)
+	}
+	if LibC(jvm) != platform.LibC() {
+		name += "-" + LibC(jvm)
+	}
+	if Arch(jvm) != platform.Arch() {
+		name += "-" + Arch(jvm)
+	}
+	return name
+}
+
+// Describe summarises what kind of build an installation is, for listings.
+func Describe(jvm config.InstalledJVM) string {
+	details := []string{jvm.Vendor, Arch(jvm)}
+	if ImageType(jvm) != provider.ImageJDK {
+		details = append(details, ImageType(jvm))
+	}
+	if jvm.JavaFX {
+		details = append(details, "javafx")
+	}
+	if LibC(jvm) != platform.LibC() {
+		details = append(details, LibC(jvm))
+	}
+	if Channel(jvm) == provider.ChannelEA {
+		details = append(details, "early access")
+	}
+	return strings.Join(details, ", ")
+}
+
+// InstalledVersion returns the version of an installation, falling back to
+// the version encoded in its name for entries recorded before versions were
+// stored.
+func InstalledVersion(name string, jvm config.InstalledJVM) (jdkversion.Version, bool) {
+	if jvm.Version != "" {
+		if v, err := jdkversion.Parse(jvm.Version); err == nil {
+			return v, true
+		}
+	}
+
+	v, err := jdkversion.Parse(strings.TrimPrefix(name, jvm.Vendor+"-"))
+	if err != nil {
+		return jdkversion.Version{}, false
+	}
+	return v, true
+}
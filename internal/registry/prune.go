@@ -0,0 +1,70 @@
+package registry
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/maskedsyntax/jvman/internal/jdkversion"
+)
+
+// Referenced returns the installations that the global default, a local
+// override or a tracked .jvman file currently resolves to.
+func (r *Registry) Referenced() map[string]bool {
+	refs := make(map[string]bool)
+	add := func(spec string) {
+		if spec == "" {
+			return
+		}
+		if name, err := r.Find(spec); err == nil {
+			refs[name] = true
+		}
+	}
+
+	add(r.cfg.Global)
+	for _, v := range r.cfg.LocalOverrides {
+		add(v)
+	}
+	for _, path := range r.cfg.LocalFiles {
+		if data, err := os.ReadFile(path); err == nil {
+			add(strings.TrimSpace(string(data)))
+		}
+	}
+	return refs
+}
+
+// Prunable returns the installations that jvman installed itself and that are
+// not referenced, not pinned, not in the system store and not among the
+// newest keep patches of their vendor, major and variant.
+// Installations whose version cannot be determined are never returned.
+func (r *Registry) Prunable(keep int) []string {
+	refs := r.Referenced()
+
+	groups := make(map[string][]candidate)
+	for _, name := range r.Names() {
+		jvm := r.cfg.Installed[name]
+		v, ok := InstalledVersion(name, jvm)
+		if !ok {
+			continue
+		}
+		group := InstallName(fmt.Sprintf("%s-%d", jvm.Vendor, v.Major()), jvm)
+		groups[group] = append(groups[group], candidate{name: name, jvm: jvm, version: v})
+	}
+
+	var prunable []string
+	for _, candidates := range groups {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return jdkversion.Compare(candidates[i].version, candidates[j].version) > 0
+		})
+		for i, c := range candidates {
+			if i < keep || refs[c.name] || c.jvm.Pinned || c.jvm.System || c.jvm.External {
+				continue
+			}
+			prunable = append(prunable, c.name)
+		}
+	}
+
+	sort.Strings(prunable)
+	return prunable
+}
@@ -0,0 +1,61 @@
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/maskedsyntax/jvman/internal/config"
+)
+
+func pruneConfig() *config.Config {
+	cfg := &config.Config{
+		LocalOverrides: make(map[string]string),
+		Installed:      make(map[string]config.InstalledJVM),
+		Aliases:        make(map[string]string),
+	}
+	for _, version := range []string{"17.0.7+7", "17.0.8+7", "17.0.9+9"} {
+		cfg.Installed["temurin-"+version] = config.InstalledJVM{
+			Path:    "/nonexistent/temurin-" + version,
+			Vendor:  "temurin",
+			Version: version,
+		}
+	}
+	return cfg
+}
+
+func TestPrunableKeepsGlobalDefault(t *testing.T) {
+	cfg := pruneConfig()
+	cfg.Global = "temurin-17.0.7+7"
+
+	got := New(cfg).Prunable(1)
+	want := []string{"temurin-17.0.8+7"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Prunable(1) = %v, want %v", got, want)
+	}
+}
+
+func TestPrunableKeepsReferences(t *testing.T) {
+	cfg := pruneConfig()
+	cfg.LocalOverrides["/src/app"] = "temurin-17.0.8+7"
+
+	pinned := cfg.Installed["temurin-17.0.7+7"]
+	pinned.Pinned = true
+	cfg.Installed["temurin-17.0.7+7"] = pinned
+
+	got := New(cfg).Prunable(0)
+	want := []string{"temurin-17.0.9+9"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Prunable(0) = %v, want %v", got, want)
+	}
+}
+
+func TestPrunableUnreferenced(t *testing.T) {
+	cfg := pruneConfig()
+	cfg.Global = "temurin-17"
+
+	got := New(cfg).Prunable(0)
+	want := []string{"temurin-17.0.7+7", "temurin-17.0.8+7"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Prunable(0) = %v, want %v", got, want)
+	}
+}
@@ -1,52 +1,164 @@
 package registry
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/maskedsyntax/jvman/internal/config"
 	"github.com/maskedsyntax/jvman/internal/paths"
 )
 
+var (
+	// ErrPinned is returned when removing a pinned installation.
+	ErrPinned = errors.New("installation is pinned")
+	// ErrSystem is returned when changing an installation from the system
+	// store through a user's registry.
+	ErrSystem = errors.New("installation is in the system store")
+	// ErrNotRunnable is returned when selecting a build for an architecture
+	// or libc this machine cannot run.
+	ErrNotRunnable = errors.New("cannot run on this machine")
+)
+
 type Registry struct {
-	cfg *config.Config
+	cfg    *config.Config
+	system bool
 }
 
 func New(cfg *config.Config) *Registry {
 	return &Registry{cfg: cfg}
 }
 
-func (r *Registry) Add(name, path, vendor string) error {
-	r.cfg.Installed[name] = config.InstalledJVM{
-		Path:   path,
-		Vendor: vendor,
+// NewSystem returns the registry of the system store, for a config from
+// config.LoadSystem.
+func NewSystem(cfg *config.Config) *Registry {
+	return &Registry{cfg: cfg, system: true}
+}
+
+// IsSystem reports whether r is the system store's registry.
+func (r *Registry) IsSystem() bool {
+	return r.system
+}
+
+// JvmsDir is where r's installations are unpacked.
+func (r *Registry) JvmsDir() (string, error) {
+	if r.system {
+		return paths.SystemJvmsDir()
 	}
-	return config.Save(r.cfg)
+	return paths.JvmsDir()
 }
 
-func (r *Registry) Remove(name string) error {
-	jvm, exists := r.cfg.Installed[name]
-	if !exists {
-		return fmt.Errorf("JVM %s is not installed", name)
+// update applies fn to the config as it is on disk now, so that changes
+// other jvman processes made since r.cfg was loaded are kept, and then
+// refreshes r.cfg.
+func (r *Registry) update(fn func(reg *Registry) error) error {
+	apply := func(cfg *config.Config) error {
+		return fn(&Registry{cfg: cfg, system: r.system})
 	}
 
-	if err := os.RemoveAll(jvm.Path); err != nil {
-		return fmt.Errorf("failed to remove JVM directory: %w", err)
+	var cfg *config.Config
+	var err error
+	if r.system {
+		cfg, err = config.UpdateSystem(apply)
+	} else {
+		cfg, err = config.Update(apply)
 	}
+	if err != nil {
+		return err
+	}
+
+	*r.cfg = *cfg
+	return nil
+}
 
-	delete(r.cfg.Installed, name)
+func (r *Registry) Add(name string, jvm config.InstalledJVM) error {
+	return r.update(func(reg *Registry) error {
+		reg.cfg.Installed[name] = jvm
+		reg.forgetRemovedPin(name)
+		return nil
+	})
+}
 
-	if r.cfg.Global == name {
-		r.cfg.Global = ""
+// Link registers a JDK that jvman did not install under a name chosen by the
+// user. The entry is marked external, so removing it leaves the JDK alone.
+func (r *Registry) Link(name string, jvm config.InstalledJVM) error {
+	if !aliasPattern.MatchString(name) {
+		return fmt.Errorf("invalid name %q: use letters, digits, '.', '_' and '-', starting with a letter", name)
 	}
 
-	for dir, v := range r.cfg.LocalOverrides {
-		if v == name {
-			delete(r.cfg.LocalOverrides, dir)
+	return r.update(func(reg *Registry) error {
+		if reg.IsInstalled(name) {
+			return fmt.Errorf("%s is already installed", name)
 		}
-	}
+		if _, ok := reg.cfg.Aliases[name]; ok {
+			return fmt.Errorf("%s is already an alias", name)
+		}
+
+		jvm.External = true
+		reg.cfg.Installed[name] = jvm
+		reg.forgetRemovedPin(name)
+		return nil
+	})
+}
 
-	return config.Save(r.cfg)
+// Remove deletes an installation and every reference to it, including
+// aliases naming it. External
+// installations are only unregistered. Pinned installations are refused; see
+// ForceRemove.
+func (r *Registry) Remove(name string) error {
+	return r.remove(name, false)
+}
+
+// ForceRemove deletes an installation even if it is pinned.
+func (r *Registry) ForceRemove(name string) error {
+	return r.remove(name, true)
+}
+
+func (r *Registry) remove(name string, force bool) error {
+	return r.update(func(reg *Registry) error {
+		jvm, exists := reg.cfg.Installed[name]
+		if !exists {
+			return fmt.Errorf("JVM %s is not installed", name)
+		}
+		if jvm.System {
+			return fmt.Errorf("%w: %s", ErrSystem, name)
+		}
+		if jvm.Pinned && !force {
+			return fmt.Errorf("%w: %s", ErrPinned, name)
+		}
+
+		if !jvm.External {
+			if err := os.RemoveAll(jvm.Path); err != nil {
+				return fmt.Errorf("failed to remove JVM directory: %w", err)
+			}
+		}
+
+		delete(reg.cfg.Installed, name)
+		if jvm.Pinned && !slices.Contains(reg.cfg.RemovedPins, name) {
+			reg.cfg.RemovedPins = append(reg.cfg.RemovedPins, name)
+		}
+
+		if reg.cfg.Global == name {
+			reg.cfg.Global = ""
+		}
+
+		for dir, v := range reg.cfg.LocalOverrides {
+			if v == name {
+				delete(reg.cfg.LocalOverrides, dir)
+			}
+		}
+
+		for alias, target := range reg.cfg.Aliases {
+			if target == name {
+				delete(reg.cfg.Aliases, alias)
+			}
+		}
+		return nil
+	})
 }
 
 func (r *Registry) Get(name string) (*config.InstalledJVM, error) {
@@ -66,18 +178,103 @@ func (r *Registry) List() map[string]config.InstalledJVM {
 	return r.cfg.Installed
 }
 
+// Names returns the installed names in a stable order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.cfg.Installed))
+	for name := range r.cfg.Installed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *Registry) SetPinned(name string, pinned bool) error {
+	return r.update(func(reg *Registry) error {
+		jvm, exists := reg.cfg.Installed[name]
+		if !exists {
+			return fmt.Errorf("JVM %s is not installed", name)
+		}
+		if jvm.System {
+			return fmt.Errorf("%w: %s", ErrSystem, name)
+		}
+
+		jvm.Pinned = pinned
+		reg.cfg.Installed[name] = jvm
+		return nil
+	})
+}
+
+// RemovedPin reports whether name, or the alias name, refers to a pinned
+// installation that was removed with --force.
+func (r *Registry) RemovedPin(name string) bool {
+	if target, ok := r.cfg.Aliases[name]; ok {
+		name = target
+	}
+	return slices.Contains(r.cfg.RemovedPins, name)
+}
+
+func (r *Registry) forgetRemovedPin(name string) {
+	r.cfg.RemovedPins = slices.DeleteFunc(r.cfg.RemovedPins, func(n string) bool {
+		return n == name
+	})
+}
+
+// SetPath records that an installation has moved to path.
+func (r *Registry) SetPath(name, path string) error {
+	return r.update(func(reg *Registry) error {
+		jvm, exists := reg.cfg.Installed[name]
+		if !exists {
+			return fmt.Errorf("JVM %s is not installed", name)
+		}
+		if jvm.System {
+			return fmt.Errorf("%w: %s", ErrSystem, name)
+		}
+
+		jvm.Path = path
+		reg.cfg.Installed[name] = jvm
+		return nil
+	})
+}
+
+// NameOf returns the installation registered at path, comparing resolved
+// symlinks.
+func (r *Registry) NameOf(path string) (string, bool) {
+	want, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		want = path
+	}
+
+	for _, name := range r.Names() {
+		have, err := filepath.EvalSymlinks(r.cfg.Installed[name].Path)
+		if err != nil {
+			have = r.cfg.Installed[name].Path
+		}
+		if have == want {
+			return name, true
+		}
+	}
+	return "", false
+}
+
 func (r *Registry) IsInstalled(name string) bool {
 	_, exists := r.cfg.Installed[name]
 	return exists
 }
 
+// SetGlobal sets the global default to an installation name or alias.
 func (r *Registry) SetGlobal(name string) error {
-	if _, err := r.Get(name); err != nil {
-		return err
-	}
+	return r.update(func(reg *Registry) error {
+		resolved, err := reg.Find(name)
+		if err != nil {
+			return err
+		}
+		if _, err := reg.Get(resolved); err != nil {
+			return err
+		}
 
-	r.cfg.Global = name
-	return config.Save(r.cfg)
+		reg.cfg.Global = name
+		return nil
+	})
 }
 
 func (r *Registry) GetGlobal() string {
@@ -85,12 +282,87 @@ func (r *Registry) GetGlobal() string {
 }
 
 func (r *Registry) SetLocalOverride(dir, name string) error {
-	if _, err := r.Get(name); err != nil {
-		return err
+	return r.update(func(reg *Registry) error {
+		resolved, err := reg.Find(name)
+		if err != nil {
+			return err
+		}
+		if _, err := reg.Get(resolved); err != nil {
+			return err
+		}
+
+		reg.cfg.LocalOverrides[dir] = name
+		return nil
+	})
+}
+
+// TrackLocalFile records a .jvman file written by jvman so that Repoint can
+// update it later.
+func (r *Registry) TrackLocalFile(path string) error {
+	for _, p := range r.cfg.LocalFiles {
+		if p == path {
+			return nil
+		}
 	}
 
-	r.cfg.LocalOverrides[dir] = name
-	return config.Save(r.cfg)
+	return r.update(func(reg *Registry) error {
+		for _, p := range reg.cfg.LocalFiles {
+			if p == path {
+				return nil
+			}
+		}
+		reg.cfg.LocalFiles = append(reg.cfg.LocalFiles, path)
+		return nil
+	})
+}
+
+// Repoint moves every reference to one installation onto another: the global
+// default, local overrides, aliases and tracked .jvman files naming it. It
+// returns the .jvman files that were rewritten.
+func (r *Registry) Repoint(from, to string) ([]string, error) {
+	var rewritten []string
+	err := r.update(func(reg *Registry) error {
+		if _, err := reg.Get(to); err != nil {
+			return err
+		}
+
+		if reg.cfg.Global == from {
+			reg.cfg.Global = to
+		}
+
+		for dir, v := range reg.cfg.LocalOverrides {
+			if v == from {
+				reg.cfg.LocalOverrides[dir] = to
+			}
+		}
+
+		for alias, target := range reg.cfg.Aliases {
+			if target == from {
+				reg.cfg.Aliases[alias] = to
+			}
+		}
+
+		var tracked []string
+		for _, path := range reg.cfg.LocalFiles {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				// Drop files that have since been deleted.
+				continue
+			}
+			tracked = append(tracked, path)
+
+			if strings.TrimSpace(string(data)) != from {
+				continue
+			}
+			if err := os.WriteFile(path, []byte(to+"\n"), 0644); err != nil {
+				return fmt.Errorf("failed to update %s: %w", path, err)
+			}
+			rewritten = append(rewritten, path)
+		}
+		reg.cfg.LocalFiles = tracked
+		return nil
+	})
+	return rewritten, err
 }
 
 func (r *Registry) FindByVersion(version, vendor string) string {
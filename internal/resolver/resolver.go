@@ -1,30 +1,61 @@
 package resolver
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/maskedsyntax/jvman/internal/config"
 	"github.com/maskedsyntax/jvman/internal/paths"
+	"github.com/maskedsyntax/jvman/internal/registry"
 )
 
 type Resolver struct {
 	cfg *config.Config
+	reg *registry.Registry
 }
 
 func New(cfg *config.Config) *Resolver {
-	return &Resolver{cfg: cfg}
+	return &Resolver{cfg: cfg, reg: registry.New(cfg)}
 }
 
 type Resolution struct {
-	Version string
-	Path    string
-	Source  string
+	Version  string
+	Path     string
+	Source   string
+	Vendor   string
+	Major    int
+	Arch     string
+	Channel  string
+	Runnable bool
+	Pinned   bool
+}
+
+func newResolution(name string, jvm config.InstalledJVM, source string) *Resolution {
+	res := &Resolution{
+		Version:  name,
+		Path:     jvm.Path,
+		Source:   source,
+		Vendor:   jvm.Vendor,
+		Arch:     registry.Arch(jvm),
+		Channel:  registry.Channel(jvm),
+		Runnable: registry.Runnable(jvm),
+		Pinned:   jvm.Pinned,
+	}
+	if v, ok := registry.InstalledVersion(name, jvm); ok {
+		res.Major = v.Major()
+	}
+	return res
 }
 
 func (r *Resolver) Resolve() (*Resolution, error) {
-	if res := r.resolveFromLocalFile(); res != nil {
+	res, err := r.resolveFromLocalFile()
+	if err != nil {
+		return nil, err
+	}
+	if res != nil {
 		return res, nil
 	}
 
@@ -39,10 +70,10 @@ func (r *Resolver) Resolve() (*Resolution, error) {
 	return nil, nil
 }
 
-func (r *Resolver) resolveFromLocalFile() *Resolution {
+func (r *Resolver) resolveFromLocalFile() (*Resolution, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
-		return nil
+		return nil, nil
 	}
 
 	dir := cwd
@@ -51,12 +82,26 @@ func (r *Resolver) resolveFromLocalFile() *Resolution {
 		data, err := os.ReadFile(localFile)
 		if err == nil {
 			version := strings.TrimSpace(string(data))
-			if jvm, exists := r.cfg.Installed[version]; exists {
-				return &Resolution{
-					Version: version,
-					Path:    jvm.Path,
-					Source:  "local file: " + localFile,
+			name, err := r.reg.Find(version)
+			var ambiguous *registry.AmbiguousError
+			if errors.As(err, &ambiguous) {
+				return nil, fmt.Errorf("%s: %w", localFile, err)
+			}
+			if errors.Is(err, registry.ErrNotFound) && r.reg.RemovedPin(version) {
+				return nil, fmt.Errorf("%s: pinned installation %s was removed; reinstall it or change the file", localFile, version)
+			}
+			// Not a name, alias or spec, e.g. an alias whose installation
+			// was removed: falling back to another Java would hide that.
+			if err != nil && !errors.Is(err, registry.ErrNotFound) {
+				return nil, fmt.Errorf("%s: %s is not an installation, alias or version spec", localFile, version)
+			}
+			if err == nil {
+				jvm := r.cfg.Installed[name]
+				// A pinned project must not silently run on something else.
+				if _, statErr := os.Stat(jvm.Path); jvm.Pinned && statErr != nil {
+					return nil, fmt.Errorf("%s: pinned installation %s is missing from %s", localFile, name, jvm.Path)
 				}
+				return newResolution(name, jvm, r.source("local file: "+localFile, version)), nil
 			}
 		}
 
@@ -67,7 +112,7 @@ func (r *Resolver) resolveFromLocalFile() *Resolution {
 		dir = parent
 	}
 
-	return nil
+	return nil, nil
 }
 
 func (r *Resolver) resolveFromLocalOverride() *Resolution {
@@ -77,12 +122,8 @@ func (r *Resolver) resolveFromLocalOverride() *Resolution {
 	}
 
 	if version, exists := r.cfg.LocalOverrides[cwd]; exists {
-		if jvm, exists := r.cfg.Installed[version]; exists {
-			return &Resolution{
-				Version: version,
-				Path:    jvm.Path,
-				Source:  "local override",
-			}
+		if name, err := r.reg.Find(version); err == nil {
+			return newResolution(name, r.cfg.Installed[name], r.source("local override", version))
 		}
 	}
 
@@ -94,17 +135,21 @@ func (r *Resolver) resolveFromGlobal() *Resolution {
 		return nil
 	}
 
-	if jvm, exists := r.cfg.Installed[r.cfg.Global]; exists {
-		return &Resolution{
-			Version: r.cfg.Global,
-			Path:    jvm.Path,
-			Source:  "global",
-		}
+	if name, err := r.reg.Find(r.cfg.Global); err == nil {
+		return newResolution(name, r.cfg.Installed[name], r.source("global", r.cfg.Global))
 	}
 
 	return nil
 }
 
+// source describes where a resolution came from, naming the alias used.
+func (r *Resolver) source(origin, version string) string {
+	if _, ok := r.reg.Alias(version); ok {
+		return fmt.Sprintf("%s (alias %s)", origin, version)
+	}
+	return origin
+}
+
 func (r *Resolver) ResolveJavaBinary() (string, error) {
 	res, err := r.Resolve()
 	if err != nil {
@@ -1,6 +1,11 @@
 package shim
 
-import "github.com/maskedsyntax/jvman/internal/paths"
+import (
+	"fmt"
+	"os"
+
+	"github.com/maskedsyntax/jvman/internal/paths"
+)
 
 var shimBinaries = []string{
 	"java",
@@ -30,3 +35,47 @@ func GetShimBinaries() []string {
 func getBinDir() (string, error) {
 	return paths.BinDir()
 }
+
+// jvmanExecutable is baked into the shims so they can fall back to jvman for
+// versions that are not plain installation names (specs, aliases, ...).
+func jvmanExecutable() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return "jvman"
+	}
+	return exe
+}
+
+// layout is where the shims look for the config and JDKs unless they run
+// with a different JVMAN_HOME. envName, if set, is exported to jvman so that
+// it agrees with the shims.
+type layout struct {
+	configDir string
+	jvmsDir   string
+	envName   string
+	envValue  string
+}
+
+// home is the JVMAN_HOME the shims were created for, if any.
+func (l layout) home() string {
+	if l.envName == paths.HomeEnv {
+		return l.envValue
+	}
+	return ""
+}
+
+func currentLayout() (layout, error) {
+	var l layout
+	var err error
+
+	if l.configDir, err = paths.ConfigDir(); err != nil {
+		return l, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	if l.jvmsDir, err = paths.JvmsDir(); err != nil {
+		return l, fmt.Errorf("failed to get jvms directory: %w", err)
+	}
+	if l.envName, l.envValue, err = paths.LayoutEnv(); err != nil {
+		return l, fmt.Errorf("failed to get jvman home: %w", err)
+	}
+	return l, nil
+}
@@ -6,8 +6,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-
-	"github.com/maskedsyntax/jvman/internal/paths"
 )
 
 type unixManager struct{}
@@ -26,14 +24,14 @@ func (m *unixManager) CreateShims() error {
 		return fmt.Errorf("failed to create bin directory: %w", err)
 	}
 
-	baseDir, err := paths.BaseDir()
+	layout, err := currentLayout()
 	if err != nil {
-		return fmt.Errorf("failed to get base directory: %w", err)
+		return err
 	}
 
 	for _, binary := range shimBinaries {
 		shimPath := filepath.Join(binDir, binary)
-		if err := createUnixShim(shimPath, binary, baseDir); err != nil {
+		if err := createUnixShim(shimPath, binary, layout, jvmanExecutable()); err != nil {
 			return fmt.Errorf("failed to create shim for %s: %w", binary, err)
 		}
 	}
@@ -55,10 +53,23 @@ func (m *unixManager) RemoveShims() error {
 	return nil
 }
 
-func createUnixShim(shimPath, binary, baseDir string) error {
+func createUnixShim(shimPath, binary string, l layout, jvmanExe string) error {
+	var env string
+	if l.envName != "" {
+		env = fmt.Sprintf("%s=\"${%s:-%s}\"\nexport %s\n", l.envName, l.envName, l.envValue, l.envName)
+	}
+
 	script := fmt.Sprintf(`#!/bin/sh
 set -e
 
+%sif [ -n "$JVMAN_HOME" ] && [ "$JVMAN_HOME" != "%s" ]; then
+    config_dir="$JVMAN_HOME"
+    jvms_dir="$JVMAN_HOME/jvms"
+else
+    config_dir="%s"
+    jvms_dir="%s"
+fi
+
 resolve_version() {
     dir="$(pwd)"
     while [ "$dir" != "/" ]; do
@@ -69,8 +80,8 @@ resolve_version() {
         dir="$(dirname "$dir")"
     done
 
-    if [ -f "%s/config.json" ]; then
-        global=$(grep -o '"global"[[:space:]]*:[[:space:]]*"[^"]*"' "%s/config.json" 2>/dev/null | head -1 | sed 's/.*"global"[[:space:]]*:[[:space:]]*"\([^"]*\)".*/\1/')
+    if [ -f "$config_dir/config.json" ]; then
+        global=$(grep -o '"global"[[:space:]]*:[[:space:]]*"[^"]*"' "$config_dir/config.json" 2>/dev/null | head -1 | sed 's/.*"global"[[:space:]]*:[[:space:]]*"\([^"]*\)".*/\1/')
         if [ -n "$global" ]; then
             echo "$global"
             return
@@ -84,14 +95,19 @@ if [ -z "$version" ]; then
     exit 1
 fi
 
-java_home="%s/jvms/$version"
+java_home="$jvms_dir/$version"
 if [ ! -d "$java_home" ]; then
+    jvman="%s"
+    [ -x "$jvman" ] || jvman=jvman
+    java_home=$("$jvman" which --home 2>/dev/null) || java_home=""
+fi
+if [ -z "$java_home" ] || [ ! -d "$java_home" ]; then
     echo "jvman: Java version '$version' is not installed. Run 'jvman install $version'." >&2
     exit 1
 fi
 
 exec "$java_home/bin/%s" "$@"
-`, baseDir, baseDir, baseDir, binary)
+`, env, l.home(), l.configDir, l.jvmsDir, jvmanExe, binary)
 
 	if err := os.WriteFile(shimPath, []byte(script), 0755); err != nil {
 		return err
@@ -24,9 +24,14 @@ func (m *windowsManager) CreateShims() error {
 		return fmt.Errorf("failed to create bin directory: %w", err)
 	}
 
+	layout, err := currentLayout()
+	if err != nil {
+		return err
+	}
+
 	for _, binary := range shimBinaries {
 		shimPath := filepath.Join(binDir, binary+".cmd")
-		if err := createWindowsShim(shimPath, binary); err != nil {
+		if err := createWindowsShim(shimPath, binary, layout, jvmanExecutable()); err != nil {
 			return fmt.Errorf("failed to create shim for %s: %w", binary, err)
 		}
 	}
@@ -50,11 +55,21 @@ func (m *windowsManager) RemoveShims() error {
 	return nil
 }
 
-func createWindowsShim(shimPath, binary string) error {
+func createWindowsShim(shimPath, binary string, l layout, jvmanExe string) error {
+	var env string
+	if l.envName != "" {
+		env = fmt.Sprintf("if not defined %s set \"%s=%s\"\n", l.envName, l.envName, l.envValue)
+	}
+
 	script := fmt.Sprintf(`@echo off
 setlocal enabledelayedexpansion
 
-set "JVMAN_HOME=%%USERPROFILE%%\.jvman"
+%sset "CONFIG_DIR=%s"
+set "JVMS_DIR=%s"
+if defined JVMAN_HOME if /i not "%%JVMAN_HOME%%"=="%s" (
+    set "CONFIG_DIR=%%JVMAN_HOME%%"
+    set "JVMS_DIR=%%JVMAN_HOME%%\jvms"
+)
 set "VERSION="
 
 rem Check for .jvman file in current and parent directories
@@ -71,8 +86,8 @@ goto :findversion
 
 :checkglobal
 rem Read global version from config.json
-if exist "%%JVMAN_HOME%%\config.json" (
-    for /f "tokens=2 delims=:," %%%%a in ('findstr /c:"\"global\"" "%%JVMAN_HOME%%\config.json"') do (
+if exist "%%CONFIG_DIR%%\config.json" (
+    for /f "tokens=2 delims=:," %%%%a in ('findstr /c:"\"global\"" "%%CONFIG_DIR%%\config.json"') do (
         set "VERSION=%%%%~a"
         set "VERSION=!VERSION:"=!"
         set "VERSION=!VERSION: =!"
@@ -85,14 +100,20 @@ if "%%VERSION%%"=="" (
     exit /b 1
 )
 
-set "JAVA_HOME=%%JVMAN_HOME%%\jvms\%%VERSION%%"
+set "JAVA_HOME=%%JVMS_DIR%%\%%VERSION%%"
 if not exist "%%JAVA_HOME%%" (
+    set "JAVA_HOME="
+    set "JVMAN_EXE=%s"
+    if not exist "!JVMAN_EXE!" set "JVMAN_EXE=jvman"
+    for /f "delims=" %%%%h in ('"!JVMAN_EXE!" which --home 2^>nul') do set "JAVA_HOME=%%%%h"
+)
+if "%%JAVA_HOME%%"=="" (
     echo jvman: Java version '%%VERSION%%' is not installed. Run 'jvman install %%VERSION%%'. >&2
     exit /b 1
 )
 
 "%%JAVA_HOME%%\bin\%s.exe" %%*
-`, binary)
+`, env, l.configDir, l.jvmsDir, l.home(), jvmanExe, binary)
 
 	return os.WriteFile(shimPath, []byte(script), 0755)
 }
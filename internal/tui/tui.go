@@ -41,9 +41,12 @@ var (
 )
 
 type item struct {
-	name     string
-	vendor   string
-	isCurrent bool
+	name       string
+	details    string
+	isCurrent  bool
+	isPinned   bool
+	isSystem   bool
+	isExternal bool
 }
 
 func (i item) Title() string {
@@ -54,7 +57,17 @@ func (i item) Title() string {
 }
 
 func (i item) Description() string {
-	return i.vendor
+	details := i.details
+	if i.isPinned {
+		details += " [pinned]"
+	}
+	if i.isSystem {
+		details += " [system]"
+	}
+	if i.isExternal {
+		details += " [external]"
+	}
+	return details
 }
 
 func (i item) FilterValue() string {
@@ -115,12 +128,17 @@ func initialModel(cfg *config.Config, reg *registry.Registry) model {
 func buildItemList(cfg *config.Config, reg *registry.Registry) []list.Item {
 	installed := reg.List()
 	items := make([]list.Item, 0, len(installed))
+	global, _ := reg.Find(cfg.Global)
 
-	for name, jvm := range installed {
+	for _, name := range reg.Names() {
+		jvm := installed[name]
 		items = append(items, item{
-			name:      name,
-			vendor:    jvm.Vendor,
-			isCurrent: name == cfg.Global,
+			name:       name,
+			details:    registry.Describe(jvm),
+			isCurrent:  name == global,
+			isPinned:   jvm.Pinned,
+			isSystem:   jvm.System,
+			isExternal: jvm.External,
 		})
 	}
 
@@ -150,7 +168,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, keys.Switch):
 			if i, ok := m.list.SelectedItem().(item); ok {
-				if err := m.reg.SetGlobal(i.name); err != nil {
+				if err := m.reg.CheckRunnable(i.name, false); err != nil {
+					m.status = fmt.Sprintf("Cannot switch: %v", err)
+				} else if err := m.reg.SetGlobal(i.name); err != nil {
 					m.status = fmt.Sprintf("Error: %v", err)
 				} else {
 					m.status = fmt.Sprintf("Switched to %s", i.name)
@@ -164,6 +184,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if i, ok := m.list.SelectedItem().(item); ok {
 				if i.isCurrent {
 					m.status = "Cannot remove the current global version"
+				} else if i.isPinned {
+					m.status = fmt.Sprintf("Cannot remove pinned version %s (run jvman unpin first)", i.name)
+				} else if i.isSystem {
+					m.status = fmt.Sprintf("Cannot remove %s from the system store (run jvman remove --system)", i.name)
 				} else {
 					if err := m.reg.Remove(i.name); err != nil {
 						m.status = fmt.Sprintf("Error: %v", err)